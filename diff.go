@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"github.com/pmtk/openshift-tests-api-usage/pkg/apiusage"
+)
+
+// runDiff implements `diff [-format text|markdown|json] OLD.json NEW.json` subcommand
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	var formatArg = fs.String("format", "text", "output format: text, markdown, json")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s diff [-format text|markdown|json] OLD_REPORT.json NEW_REPORT.json\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	oldR, err := apiusage.LoadReport(fs.Arg(0))
+	if err != nil {
+		klog.Exitf("%v", err)
+	}
+	newR, err := apiusage.LoadReport(fs.Arg(1))
+	if err != nil {
+		klog.Exitf("%v", err)
+	}
+	d := apiusage.DiffReports(oldR, newR)
+
+	switch *formatArg {
+	case "text":
+		printDiffText(os.Stdout, d)
+	case "markdown":
+		printDiffMarkdown(os.Stdout, d)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(d); err != nil {
+			klog.Exitf("Failed to encode diff: %v", err)
+		}
+	default:
+		klog.Exitf("Unknown format: %s", *formatArg)
+	}
+}
+
+func printDiffText(w io.Writer, d *apiusage.ReportDiff) {
+	if d.Empty() {
+		fmt.Fprintf(w, "No changes in API usage\n")
+		return
+	}
+	if len(d.AddedGroups) != 0 || len(d.RemovedGroups) != 0 {
+		fmt.Fprintf(w, "API Groups:\n")
+		for _, g := range d.AddedGroups {
+			fmt.Fprintf(w, "\t+ %s\n", g)
+		}
+		for _, g := range d.RemovedGroups {
+			fmt.Fprintf(w, "\t- %s\n", g)
+		}
+	}
+	if len(d.Tests) != 0 {
+		fmt.Fprintf(w, "Tests:\n")
+		for _, t := range d.Tests {
+			fmt.Fprintf(w, "\t%s (%s)\n", t.Name, t.Status)
+			for _, g := range t.AddedGroups {
+				fmt.Fprintf(w, "\t\t+ %s\n", g)
+			}
+			for _, g := range t.RemovedGroups {
+				fmt.Fprintf(w, "\t\t- %s\n", g)
+			}
+		}
+	}
+	if len(d.NewUnresolved) != 0 {
+		fmt.Fprintf(w, "New unresolved call sites:\n")
+		for _, diag := range d.NewUnresolved {
+			fmt.Fprintf(w, "\t%v: %s\n", diag.Position, diag.Message)
+		}
+	}
+}
+
+func printDiffMarkdown(w io.Writer, d *apiusage.ReportDiff) {
+	fmt.Fprintf(w, "### API usage changes\n\n")
+	if d.Empty() {
+		fmt.Fprintf(w, "No changes in API usage.\n")
+		return
+	}
+
+	code := func(xs []string) string {
+		res := make([]string, 0, len(xs))
+		for _, x := range xs {
+			res = append(res, "`"+x+"`")
+		}
+		return strings.Join(res, ", ")
+	}
+
+	if len(d.AddedGroups) != 0 || len(d.RemovedGroups) != 0 {
+		fmt.Fprintf(w, "**API Groups**\n\n")
+		if len(d.AddedGroups) != 0 {
+			fmt.Fprintf(w, "- Added: %s\n", code(d.AddedGroups))
+		}
+		if len(d.RemovedGroups) != 0 {
+			fmt.Fprintf(w, "- Removed: %s\n", code(d.RemovedGroups))
+		}
+		fmt.Fprintf(w, "\n")
+	}
+	if len(d.Tests) != 0 {
+		fmt.Fprintf(w, "**Tests**\n\n")
+		fmt.Fprintf(w, "| Test | Status | Added | Removed |\n")
+		fmt.Fprintf(w, "|---|---|---|---|\n")
+		for _, t := range d.Tests {
+			name := strings.ReplaceAll(t.Name, "|", "\\|")
+			fmt.Fprintf(w, "| %s | %s | %s | %s |\n", name, t.Status, code(t.AddedGroups), code(t.RemovedGroups))
+		}
+		fmt.Fprintf(w, "\n")
+	}
+	if len(d.NewUnresolved) != 0 {
+		fmt.Fprintf(w, "**New unresolved call sites**\n\n")
+		for _, diag := range d.NewUnresolved {
+			fmt.Fprintf(w, "- `%v`: %s\n", diag.Position, diag.Message)
+		}
+	}
+}
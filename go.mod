@@ -1,16 +1,19 @@
 module github.com/pmtk/openshift-tests-api-usage
 
-go 1.18
+// go 1.25 is required by golang.org/x/tools v0.44, which provides go/packages export data loading,
+// go/cfg and go/callgraph used by pkg/apiusage; go/ast.Unparen needs go 1.22 or newer.
+go 1.25.0
 
 require (
-	golang.org/x/tools v0.1.12
+	golang.org/x/tools v0.44.0
 	k8s.io/klog/v2 v2.70.1
+	sigs.k8s.io/yaml v1.4.0
 )
 
 require (
 	github.com/go-logr/logr v1.2.3 // indirect
-	golang.org/x/mod v0.6.0-dev.0.20220419223038-86c51ed26bb4 // indirect
-	golang.org/x/sys v0.1.0 // indirect
+	golang.org/x/mod v0.35.0 // indirect
+	golang.org/x/sync v0.20.0 // indirect
 )
 
 replace github.com/pmtk/openshift-tests-api-usage/test_data => ./test_data
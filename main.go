@@ -1,98 +1,206 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
-	"io/fs"
 	"os"
-	"path/filepath"
 	"regexp"
 	"strings"
+	"text/tabwriter"
+	"time"
 
-	"golang.org/x/tools/go/packages"
 	"k8s.io/klog/v2"
+
+	"github.com/pmtk/openshift-tests-api-usage/pkg/apiusage"
 )
 
 func main() {
 	defer klog.Flush()
 
-	var originPathArg = flag.String("origin", "", "path to origin repository")
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		runDiff(os.Args[2:])
+		return
+	}
+
+	var repoPathArg = flag.String("repo", "", "path to repository with tests")
+	var originPathArg = flag.String("origin", "", "path to origin repository (alias of -repo)")
 	var testdirFilterArg = flag.String("filter", "", "regexp to filter test dirs")
+	var includeArg = flag.String("include", "", "comma separated list of dirs (relative to -repo) to scan for tests (default \""+strings.Join(apiusage.DefaultIncludeRoots, ",")+"\")")
+	var excludeArg = flag.String("exclude", strings.Join(apiusage.DefaultExcludes, ","), "comma separated list of glob patterns of dir names to skip")
+	var pkgsArg = flag.String("pkgs", "", "comma separated list of package patterns (e.g. ./test/...) to analyze instead of scanning -include dirs")
+	var frameworksArg = flag.String("frameworks", "", "comma separated list of test frameworks to recognize: ginkgo, ginkgo/v2, testing (default: auto-detect)")
+	var groupsArg = flag.String("groups", strings.Join(apiusage.DefaultGroups, ","), "comma separated list of glob patterns of API Groups to report, patterns prefixed with ! exclude groups (core API Group is matched as \"core\")")
+	var kubernetesGroupsArg = flag.Bool("kubernetes-groups", false, "report upstream Kubernetes API Groups (core, apps, *.k8s.io, ...) too, unless excluded by -groups")
+	var callGraphArg = flag.String("callgraph", "", "attribute API call sites reachable from tests using call graph: cha, vta (default: disabled)")
+	var depthArg = flag.Int("depth", 0, "max call depth from test's body when -callgraph is used (0: unlimited)")
+	var explainArg = flag.Bool("explain", false, "show how API Groups of each call site were resolved")
+	var jsonArg = flag.Bool("json", false, "print report as JSON")
+	var truthArg = flag.String("truth", "", "path to YAML file with ground truth (test name -> API Groups); prints scorecard instead of the report")
+	var sinceArg = flag.String("since", "", "analyze only packages affected by changes made since given git ref")
+	var previousArg = flag.String("previous", "", "JSON report of earlier analysis to merge with results of -since")
+	var statsArg = flag.Bool("stats", false, "print wall time and peak memory of the analysis to stderr")
+	var parallelismArg = flag.Int("j", 0, "number of packages analyzed concurrently (0: number of CPUs)")
 	flag.Parse()
-	var rx *regexp.Regexp
-	if *testdirFilterArg != "" {
-		rx = regexp.MustCompile(*testdirFilterArg)
+
+	if *repoPathArg == "" {
+		repoPathArg = originPathArg
+	}
+	if *repoPathArg == "" {
+		klog.Fatalf("Provide path to repository using -repo")
 	}
 
-	if *originPathArg == "" {
-		klog.Fatalf("Provide path to origin repository using -origin")
+	cfg := apiusage.Config{
+		RepoPath:         *repoPathArg,
+		IncludeRoots:     splitList(*includeArg),
+		Excludes:         splitList(*excludeArg),
+		Patterns:         splitList(*pkgsArg),
+		Groups:           splitList(*groupsArg),
+		KubernetesGroups: *kubernetesGroupsArg,
+		CallGraph:        apiusage.CallGraphAlgorithm(*callGraphArg),
+		CallGraphDepth:   *depthArg,
+		Explain:          *explainArg,
+		Since:            *sinceArg,
+		Parallelism:      *parallelismArg,
+		Stats:            *statsArg,
+	}
+	if *previousArg != "" {
+		previous, err := apiusage.LoadReport(*previousArg)
+		if err != nil {
+			klog.Exitf("Failed to load previous report: %v", err)
+		}
+		cfg.Previous = previous
 	}
-	if exists, err := checkIfPathExists(*originPathArg); !exists {
-		klog.Exitf("Path %s does not exist", *originPathArg)
-	} else if err != nil {
-		klog.Exitf("Error occurred when checking if path %s exists: %v", *originPathArg, err)
+	if *testdirFilterArg != "" {
+		cfg.DirFilter = regexp.MustCompile(*testdirFilterArg)
+	}
+	for _, fw := range splitList(*frameworksArg) {
+		cfg.Frameworks = append(cfg.Frameworks, apiusage.Framework(fw))
 	}
 
-	astPkgs, err := getASTpackages(*originPathArg, getDirsToScan(*originPathArg, rx))
+	report, err := apiusage.Analyze(context.Background(), cfg)
 	if err != nil {
-		panic(err)
+		klog.Exitf("Analysis failed: %v", err)
 	}
-	for _, astPkg := range astPkgs {
-		for _, e := range astPkg.Errors {
-			if !strings.Contains(e.Msg, "no Go files") {
-				panic(e)
-			}
+	if report.Stats != nil {
+		printStats(report.Stats)
+	}
+	var output any = report
+	if *truthArg != "" {
+		gt, err := apiusage.LoadGroundTruth(*truthArg)
+		if err != nil {
+			klog.Exitf("Failed to load ground truth: %v", err)
 		}
+		output = apiusage.NewScorecard(report, gt)
 	}
 
-	for _, astPkg := range astPkgs {
-		if len(astPkg.Errors) == 0 {
-			workOnAstPkg(astPkg)
+	if *jsonArg {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(output); err != nil {
+			klog.Exitf("Failed to encode output: %v", err)
 		}
+		return
+	}
+	switch output := output.(type) {
+	case *apiusage.Report:
+		printReport(output)
+	case *apiusage.Scorecard:
+		printScorecard(output)
 	}
 }
 
-func checkIfPathExists(path string) (bool, error) {
-	_, err := os.Stat(path)
-	if err == nil {
-		return true, nil
-	}
-	if os.IsNotExist(err) {
-		return false, nil
+func printStats(s *apiusage.Stats) {
+	w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "packages analyzed:\t%d\n", s.Packages)
+	fmt.Fprintf(w, "packages loaded from source:\t%d (%d on demand)\n", s.SourcePackages, s.OnDemandPackages)
+	fmt.Fprintf(w, "load:\t%v\n", s.LoadTime.Round(time.Millisecond))
+	fmt.Fprintf(w, "analysis:\t%v\n", s.AnalysisTime.Round(time.Millisecond))
+	fmt.Fprintf(w, "call graph:\t%v\n", s.CallGraphTime.Round(time.Millisecond))
+	fmt.Fprintf(w, "total:\t%v\n", s.TotalTime.Round(time.Millisecond))
+	fmt.Fprintf(w, "peak memory:\t%d MiB\n", s.PeakMemory>>20)
+	fmt.Fprintf(w, "allocated:\t%d MiB\n", s.TotalAlloc>>20)
+	w.Flush()
+}
+
+func splitList(s string) []string {
+	res := []string{}
+	for _, x := range strings.Split(s, ",") {
+		if x = strings.TrimSpace(x); x != "" {
+			res = append(res, x)
+		}
 	}
-	return false, err
+	return res
 }
 
-func getDirsToScan(originPath string, filter *regexp.Regexp) []string {
-	originPkgs := func() []string {
-		res := []string{}
-		_ = filepath.WalkDir(originPath+"/test/extended/", func(path string, d fs.DirEntry, err error) error {
-			if err != nil {
-				klog.Fatalf("Error when walking origin's dir tree: %v", err)
+func printReport(r *apiusage.Report) {
+	for _, t := range r.Tests {
+		fmt.Printf("%s\n", t.Name)
+		fmt.Printf("\tAPI Groups: %v\n", groupNames(t.APIGroups))
+		for _, cs := range t.CallSites {
+			may := ""
+			if cs.May {
+				may = " (may)"
 			}
-			if d.IsDir() &&
-				!strings.Contains(path, "testdata") &&
-				(filter == nil || (filter != nil && filter.MatchString(path))) {
-				res = append(res, path)
+			fmt.Printf("\t\t%v: %v%s\n", cs.Position, groupNames(cs.APIGroups), may)
+			if len(cs.Resources) != 0 {
+				fmt.Printf("\t\t\tresources: %v\n", cs.Resources)
 			}
-			return nil
-		})
-		return res
-	}()
-	return originPkgs
+			for _, e := range cs.Via {
+				fmt.Printf("\t\t\tvia %s -> %s at %v\n", e.Caller, e.Callee, e.Position)
+			}
+			for _, step := range cs.Explanation {
+				fmt.Printf("\t\t\t%v: %s\n", step.Position, step.Code)
+			}
+		}
+	}
+	if len(r.Unattributed) != 0 {
+		fmt.Printf("Call sites outside of tests:\n")
+		for _, cs := range r.Unattributed {
+			fmt.Printf("\t%v: %v\n", cs.Position, groupNames(cs.APIGroups))
+		}
+	}
+	if len(r.Diagnostics) != 0 {
+		fmt.Printf("Diagnostics:\n")
+		for _, d := range r.Diagnostics {
+			fmt.Printf("\t%v: %s\n", d.Position, d.Message)
+			if d.Construct != nil {
+				fmt.Printf("\t\tat %v\n", *d.Construct)
+			}
+		}
+	}
 }
 
-func getASTpackages(originPath string, pkgs []string) ([]*packages.Package, error) {
-	astCfg := packages.Config{
-		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles |
-			packages.NeedImports | packages.NeedTypes | packages.NeedTypesSizes |
-			packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedDeps,
-		Dir: originPath,
-		//BuildFlags: []string{"-N", "-l"},
+// groupNames returns API Groups for printing, with the core API Group written as "core"
+func groupNames(groups []string) []string {
+	names := make([]string, 0, len(groups))
+	for _, g := range groups {
+		if g == "" {
+			g = "core"
+		}
+		names = append(names, g)
 	}
-	ppkgs, err := packages.Load(&astCfg, pkgs...)
-	if err != nil {
-		return nil, fmt.Errorf("packages.Load failed: %w", err)
+	return names
+}
+
+func printScorecard(sc *apiusage.Scorecard) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "PACKAGE\tTESTS\tFULLY\tPARTIALLY\tUNRESOLVED\tPRECISION\tRECALL\n")
+	printScore := func(name string, s apiusage.Score) {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%.2f\t%.2f\n",
+			name, s.Tests, s.FullyResolved, s.PartiallyResolved, s.Unresolved, s.Precision, s.Recall)
+	}
+	for _, p := range sc.Packages {
+		printScore(p.Package, p.Score)
+	}
+	printScore("OVERALL", sc.Overall)
+	w.Flush()
+
+	if len(sc.Missing) != 0 {
+		fmt.Printf("\nTests from ground truth not found by the analyzer:\n")
+		for _, name := range sc.Missing {
+			fmt.Printf("\t%s\n", name)
+		}
 	}
-	return ppkgs, nil
 }
@@ -0,0 +1,250 @@
+package apiusage
+
+import (
+	"go/ast"
+	"runtime"
+	"sync"
+
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/packages"
+)
+
+// apiCall is a place in the code where API is accessed
+type apiCall struct {
+	pkg    *packages.Package
+	call   *ast.CallExpr
+	callee *apiCallee
+	// stack of nodes enclosing the call, starting with *ast.File
+	stack []ast.Node
+	site  CallSite
+	// ignored is true if all API Groups used by the call site were filtered out
+	ignored bool
+}
+
+// enclosingFunc returns innermost *ast.FuncDecl or *ast.FuncLit containing the call
+func (c *apiCall) enclosingFunc() ast.Node {
+	for idx := len(c.stack) - 1; idx >= 0; idx-- {
+		switch n := c.stack[idx].(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return n
+		}
+	}
+	return nil
+}
+
+// analyzer holds the state of analysis shared between packages.
+// It's safe for concurrent use.
+type analyzer struct {
+	cfg Config
+
+	// summaries caches results of analysis of functions returning GVRs, shared by all investigators
+	summaries *summaryCache
+	// loader loads syntax of dependencies on demand
+	loader *packageLoader
+	groups *groupFilter
+	// decls indexes declarations of identifiers, shared by all investigators
+	decls *declarations
+
+	// mu guards fields below
+	mu     sync.Mutex
+	report *Report
+	tests  map[*spec]*TestUsage
+	// apiCalls caches API call sites found in each package
+	apiCalls map[*packages.Package]*pkgAPICalls
+	// attributed contains API call sites that were linked to at least one test
+	attributed map[*apiCall]bool
+}
+
+// pkgAPICalls holds API call sites of a package, they're resolved only once
+type pkgAPICalls struct {
+	once  sync.Once
+	calls []*apiCall
+}
+
+func newAnalyzer(cfg Config, loader *packageLoader, groups *groupFilter) *analyzer {
+	return &analyzer{
+		cfg:        cfg,
+		summaries:  newSummaryCache(),
+		loader:     loader,
+		groups:     groups,
+		decls:      newDeclarations(),
+		report:     &Report{},
+		tests:      map[*spec]*TestUsage{},
+		apiCalls:   map[*packages.Package]*pkgAPICalls{},
+		attributed: map[*apiCall]bool{},
+	}
+}
+
+// workers returns number of goroutines to use for analysis
+func (a *analyzer) workers() int {
+	if a.cfg.Parallelism > 0 {
+		return a.cfg.Parallelism
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// collectAPICalls returns all API call sites in the package without resolving them
+func collectAPICalls(pkg *packages.Package) []*apiCall {
+	calls := []*apiCall{}
+	matcher := newAPICallMatcher(pkg.Types)
+	i := inspector.New(pkg.Syntax)
+	i.WithStack(
+		[]ast.Node{&ast.CallExpr{}},
+		func(n ast.Node, push bool, stack []ast.Node) (proceed bool) {
+			proceed = true
+			if !push {
+				return
+			}
+
+			callExpr := n.(*ast.CallExpr)
+			callee := matcher.apiCallee(pkg.TypesInfo, callExpr)
+			if callee == nil {
+				return
+			}
+			calls = append(calls, &apiCall{
+				pkg:    pkg,
+				call:   callExpr,
+				callee: callee,
+				stack:  append([]ast.Node{}, stack...),
+				site:   CallSite{Position: pkg.Fset.Position(n.Pos())},
+			})
+			return
+		},
+	)
+	return calls
+}
+
+// resolveAPICall finds API Groups used by the call site
+func (a *analyzer) resolveAPICall(c *apiCall) {
+	inv := investigator{pkg: c.pkg, root: c.stack[0].(*ast.File), summaries: a.summaries, loader: a.loader, decls: a.decls}
+	if a.cfg.Explain {
+		inv.trace = &trace{}
+	}
+	may := false
+	inv.may = &may
+	resources := []Resource{}
+	inv.resources = &resources
+	groups, err := inv.resolve(c.call, c.callee)
+	if err == errNotAPICall {
+		c.ignored = true
+		return
+	}
+	if err != nil {
+		d := Diagnostic{Position: c.site.Position, Message: err.Error()}
+		if u, ok := err.(unsupportedError); ok {
+			d.Construct = &u.pos
+		}
+		a.mu.Lock()
+		a.report.Diagnostics = append(a.report.Diagnostics, d)
+		a.mu.Unlock()
+	}
+	if len(groups) != 0 {
+		groups = a.groups.filter(groups)
+		c.ignored = len(groups) == 0
+	}
+	c.site.APIGroups = groups
+	resources = filter(resources, func(r Resource) bool { return a.groups.matches(r.Group) })
+	if len(resources) != 0 {
+		c.site.Resources = uniqueSortedResources(resources)
+	}
+	c.site.May = may && len(groups) > 1
+	c.site.GroupClasses = classifyGroups(groups)
+	if inv.trace != nil {
+		c.site.Explanation = inv.trace.steps
+	}
+}
+
+// findAPICalls returns all API call sites in the package together with API Groups they use.
+// Results are cached, so problems with resolving are reported as Diagnostics just once.
+// If resolve is false, call sites are only collected and caller is responsible for resolving them
+// (so call sites of many packages can be resolved in parallel).
+func (a *analyzer) findAPICalls(pkg *packages.Package, resolve bool) []*apiCall {
+	a.mu.Lock()
+	pc, ok := a.apiCalls[pkg]
+	if !ok {
+		pc = &pkgAPICalls{}
+		a.apiCalls[pkg] = pc
+	}
+	a.mu.Unlock()
+
+	pc.once.Do(func() {
+		pc.calls = collectAPICalls(pkg)
+		if resolve {
+			for _, c := range pc.calls {
+				a.resolveAPICall(c)
+			}
+		}
+	})
+	return pc.calls
+}
+
+// findSpecs returns tests defined in the package using given frameworks
+func (a *analyzer) findSpecs(pkg *packages.Package, frameworks []Framework) []*spec {
+	specs := []*spec{}
+	if hasFramework(frameworks, FrameworkGinkgoV1, FrameworkGinkgoV2) {
+		specs = append(specs, findSpecs(pkg)...)
+	}
+	if hasFramework(frameworks, FrameworkGoTest) {
+		specs = append(specs, findGoTests(pkg, a.decls)...)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, s := range specs {
+		a.addTest(s, pkg)
+	}
+	return specs
+}
+
+// addTest registers test in the report, so it's present even if no API usage is found.
+// a.mu must be held.
+func (a *analyzer) addTest(s *spec, pkg *packages.Package) *TestUsage {
+	if _, ok := a.tests[s]; !ok {
+		a.tests[s] = &TestUsage{
+			Name:         s.name,
+			Framework:    s.framework,
+			Package:      pkg.PkgPath,
+			Position:     pkg.Fset.Position(s.node.Pos()),
+			APIGroups:    []string{},
+			GroupClasses: map[string]GroupClass{},
+			CallSites:    []CallSite{},
+		}
+	}
+	return a.tests[s]
+}
+
+// attribute links API call site to a test
+func (a *analyzer) attribute(s *spec, pkg *packages.Package, c *apiCall, cs CallSite) {
+	if c.ignored {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.addTest(s, pkg).addCallSite(cs)
+	a.attributed[c] = true
+}
+
+// attributeLexically links API call sites to tests that contain them in their bodies
+func (a *analyzer) attributeLexically(pkg *packages.Package, specs []*spec) {
+	for _, c := range a.findAPICalls(pkg, true) {
+		for _, s := range specsForNode(specs, c.stack) {
+			a.attribute(s, pkg, c, c.site)
+		}
+	}
+}
+
+// finish fills the report with attributed tests and call sites that weren't linked to any test
+func (a *analyzer) finish(pkgs []*packages.Package) *Report {
+	for _, t := range a.tests {
+		a.report.Tests = append(a.report.Tests, *t)
+	}
+	for _, pkg := range pkgs {
+		for _, c := range a.findAPICalls(pkg, true) {
+			if !a.attributed[c] && !c.ignored {
+				a.report.Unattributed = append(a.report.Unattributed, c.site)
+			}
+		}
+	}
+	a.report.relativize(a.cfg.RepoPath)
+	a.report.sort()
+	return a.report
+}
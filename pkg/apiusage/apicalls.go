@@ -0,0 +1,231 @@
+package apiusage
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"path"
+	"strings"
+)
+
+// apiCallKind tells how API accessed by the call is given
+type apiCallKind int
+
+const (
+	// gvrCall takes GVR argument: Resource(gvr)
+	gvrCall apiCallKind = iota
+	// restPathCall takes segments of REST path: AbsPath("/apis", group, version)
+	restPathCall
+	// rawCLICall runs oc with --raw flag followed by REST path: oc.Run("get").Args("--raw", path)
+	rawCLICall
+	// typedCall is a call of typed client of clientset generated by client-gen: CoreV1().Pods(ns).List(...)
+	typedCall
+)
+
+// apiCallee is a function or method whose calls access the API
+type apiCallee struct {
+	// pkg is an import path of the package, or its suffix for packages copied across repositories (test/extended/util)
+	pkg string
+	// recv is a name of type declaring the method, empty for functions
+	recv string
+	name string
+	kind apiCallKind
+}
+
+// apiCallees lists calls considered to be API call sites
+var apiCallees = []apiCallee{
+	{pkg: "k8s.io/client-go/dynamic", recv: "Interface", name: "Resource"},
+	{pkg: "k8s.io/client-go/metadata", recv: "Interface", name: "Resource"},
+	{pkg: "k8s.io/client-go/dynamic/dynamicinformer", recv: "DynamicSharedInformerFactory", name: "ForResource"},
+	{pkg: "k8s.io/client-go/dynamic/dynamicinformer", name: "NewFilteredDynamicInformer"},
+	{pkg: "k8s.io/client-go/dynamic/dynamiclister", name: "New"},
+	{pkg: "k8s.io/client-go/metadata/metadatainformer", recv: "SharedInformerFactory", name: "ForResource"},
+	{pkg: "k8s.io/client-go/metadata/metadatainformer", name: "NewFilteredMetadataInformer"},
+	{pkg: "k8s.io/client-go/metadata/metadatalister", name: "New"},
+	{pkg: "k8s.io/client-go/rest", recv: "Request", name: "AbsPath", kind: restPathCall},
+	{pkg: "k8s.io/client-go/rest", recv: "Request", name: "RequestURI", kind: restPathCall},
+	{pkg: "test/extended/util", recv: "CLI", name: "Run", kind: rawCLICall},
+	{pkg: "test/extended/util", recv: "CLI", name: "Args", kind: rawCLICall},
+	{pkg: "k8s.io/client-go/kubernetes", kind: typedCall},
+	{pkg: "github.com/openshift/client-go", kind: typedCall},
+}
+
+// apiCallMatcher recognizes API call sites of a package using type checker
+type apiCallMatcher struct {
+	pkg *types.Package
+	// ifaces caches interfaces of apiCallees found among packages imported by pkg, nil if not imported
+	ifaces map[*apiCallee]*types.Interface
+}
+
+func newAPICallMatcher(pkg *types.Package) *apiCallMatcher {
+	return &apiCallMatcher{pkg: pkg, ifaces: map[*apiCallee]*types.Interface{}}
+}
+
+// apiCallee returns callee of the call if it's one of apiCallees, or nil. Methods are matched by the type of receiver,
+// which has to implement the interface: e.g. dynamic.Interface itself (`oc.AdminDynamicClient().Resource(gvr)`),
+// a struct embedding it, or a wrapper implementing it. Other methods with the same name are not API calls.
+// Calls running oc are API calls only if they pass --raw flag.
+func (m *apiCallMatcher) apiCallee(info *types.Info, ce *ast.CallExpr) *apiCallee {
+	id := calleeIdent(ce)
+	if id == nil {
+		return nil
+	}
+	f, ok := info.Uses[id].(*types.Func)
+	if !ok || f.Pkg() == nil {
+		return nil
+	}
+	recv := f.Type().(*types.Signature).Recv()
+
+	for idx := range apiCallees {
+		c := &apiCallees[idx]
+		if c.kind == typedCall {
+			if _, _, ok := typedResource(info, ce, c); ok {
+				return c
+			}
+			continue
+		}
+		if c.name != f.Name() || !m.matches(c, f, recv) {
+			continue
+		}
+		if c.kind == rawCLICall && rawFlagIndex(info, ce) == -1 {
+			return nil
+		}
+		if p, ok := constantPath(info, c, ce); ok {
+			if _, ok := parseRESTPath(p); !ok {
+				// /healthz, /metrics
+				return nil
+			}
+		}
+		return c
+	}
+	return nil
+}
+
+// matches checks if function f (with receiver recv, if it's a method) is the apiCallee
+func (m *apiCallMatcher) matches(c *apiCallee, f *types.Func, recv *types.Var) bool {
+	if c.recv == "" {
+		return recv == nil && isPkg(f.Pkg().Path(), c.pkg)
+	}
+	if recv == nil {
+		return false
+	}
+	if isNamed(recv.Type(), c.pkg, c.recv) {
+		// method of the type, or promoted from embedded interface
+		return true
+	}
+	// wrapper implementing the interface
+	iface := m.iface(c)
+	return iface != nil && types.Implements(recv.Type(), iface)
+}
+
+// iface returns interface declaring the method of apiCallee, if it's imported by the package (directly or not)
+func (m *apiCallMatcher) iface(c *apiCallee) *types.Interface {
+	if iface, ok := m.ifaces[c]; ok {
+		return iface
+	}
+	var iface *types.Interface
+	seen := map[*types.Package]bool{}
+	var visit func(p *types.Package)
+	visit = func(p *types.Package) {
+		if seen[p] || iface != nil {
+			return
+		}
+		seen[p] = true
+		if isPkg(p.Path(), c.pkg) {
+			if tn, ok := p.Scope().Lookup(c.recv).(*types.TypeName); ok {
+				iface, _ = tn.Type().Underlying().(*types.Interface)
+			}
+			return
+		}
+		for _, imp := range p.Imports() {
+			visit(imp)
+		}
+	}
+	visit(m.pkg)
+	m.ifaces[c] = iface
+	return iface
+}
+
+// isPkg checks if the import path is pkg, or ends with it
+func isPkg(path, pkg string) bool {
+	return path == pkg || strings.HasSuffix(path, "/"+pkg)
+}
+
+// isNamed checks if t is (a pointer to) the named type declared in the package
+func isNamed(t types.Type, pkg, name string) bool {
+	named, ok := deref(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && isPkg(named.Obj().Pkg().Path(), pkg) && named.Obj().Name() == name
+}
+
+// constantPath returns REST path passed to the call, if it's constant
+func constantPath(info *types.Info, c *apiCallee, ce *ast.CallExpr) (string, bool) {
+	constantString := func(e ast.Expr) (string, bool) {
+		v := info.Types[e].Value
+		if v == nil || v.Kind() != constant.String {
+			return "", false
+		}
+		return constant.StringVal(v), true
+	}
+
+	switch c.kind {
+	case restPathCall:
+		if ce.Ellipsis.IsValid() {
+			return "", false
+		}
+		segs := []string{}
+		for _, arg := range ce.Args {
+			s, ok := constantString(arg)
+			if !ok {
+				return "", false
+			}
+			segs = append(segs, s)
+		}
+		return path.Join(segs...), true
+	case rawCLICall:
+		idx := rawFlagIndex(info, ce)
+		if p, ok := constantString(ce.Args[idx]); ok && p != "--raw" {
+			return strings.TrimPrefix(p, "--raw="), true
+		}
+		if constantPrefix(info, ce.Args[idx]) == "--raw" && idx+1 < len(ce.Args) {
+			return constantString(ce.Args[idx+1])
+		}
+	}
+	return "", false
+}
+
+// rawFlagIndex returns index of argument "--raw" or "--raw=PATH" of the call, or -1.
+// Only the flag has to be constant, PATH might be concatenated: "--raw=/apis/" + group.
+func rawFlagIndex(info *types.Info, ce *ast.CallExpr) int {
+	for idx, arg := range ce.Args {
+		if s := constantPrefix(info, arg); s == "--raw" || strings.HasPrefix(s, "--raw=") {
+			return idx
+		}
+	}
+	return -1
+}
+
+// constantPrefix returns the constant string expression, or its leftmost constant operand if it's a concatenation
+func constantPrefix(info *types.Info, e ast.Expr) string {
+	for {
+		if v := info.Types[e].Value; v != nil && v.Kind() == constant.String {
+			return constant.StringVal(v)
+		}
+		be, ok := ast.Unparen(e).(*ast.BinaryExpr)
+		if !ok {
+			return ""
+		}
+		e = be.X
+	}
+}
+
+// gvrArg returns the argument of API call which is GVR, or nil if it's not passed as a single argument
+func gvrArg(info *types.Info, ce *ast.CallExpr) ast.Expr {
+	sig, ok := info.TypeOf(ce.Fun).(*types.Signature)
+	assert(ok)
+	for k := 0; k < sig.Params().Len() && k < len(ce.Args); k++ {
+		if isTypeGVR(sig.Params().At(k).Type()) {
+			return ce.Args[k]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+// Package apiusage statically analyzes tests of openshift/origin and reports OpenShift API Groups used by each test.
+package apiusage
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+var (
+	// DefaultIncludeRoots are used when Config.IncludeRoots and Config.Patterns are empty.
+	DefaultIncludeRoots = []string{"test/extended"}
+
+	// DefaultExcludes are used when Config.Excludes is nil.
+	DefaultExcludes = []string{"testdata"}
+)
+
+// Config configures the analysis.
+type Config struct {
+	// RepoPath is a path to the repository containing tests, e.g. origin or an operator repository.
+	RepoPath string
+
+	// IncludeRoots are directories, relative to RepoPath, which are walked to find test packages.
+	// Defaults to DefaultIncludeRoots. Ignored if Patterns are set.
+	IncludeRoots []string
+
+	// Excludes are glob patterns (see filepath.Match) matched against directory names.
+	// Matching directories and their subdirectories are skipped when walking IncludeRoots.
+	// Defaults to DefaultExcludes.
+	Excludes []string
+
+	// Patterns are package patterns (e.g. "./test/...") passed directly to the package loader
+	// instead of walking IncludeRoots. Path patterns are loaded within the module containing them.
+	Patterns []string
+
+	// DirFilter, if set, limits analyzed test dirs to those matching the regexp.
+	DirFilter *regexp.Regexp
+
+	// Frameworks limits test frameworks that are recognized.
+	// If empty, frameworks are detected automatically based on package's imports.
+	Frameworks []Framework
+
+	// Groups are glob patterns (see path.Match) of API Groups that are reported, patterns prefixed with "!"
+	// exclude groups (e.g. "*.openshift.io", "!operator.openshift.io"). Core API Group is matched as "core".
+	// Call sites using only groups that are filtered out are dropped. Defaults to DefaultGroups.
+	Groups []string
+
+	// KubernetesGroups makes upstream Kubernetes API Groups (GroupClassCore and GroupClassKubernetes, e.g. used by
+	// typed clients of k8s.io/client-go/kubernetes) reported too, unless excluded by Groups: tests are then expected
+	// to carry [apigroup:] tags of them as well. Core API Group is tagged as "core".
+	KubernetesGroups bool
+
+	// CallGraph enables attribution of API call sites reachable from tests' bodies (e.g. in helper functions)
+	// using call graph constructed with selected algorithm. Disabled by default.
+	CallGraph CallGraphAlgorithm
+
+	// CallGraphDepth limits how many calls deep from the test's body API call sites are looked for.
+	// Zero means no limit.
+	CallGraphDepth int
+
+	// Explain enables recording of steps taken to resolve API Groups of each call site (see CallSite.Explanation).
+	Explain bool
+
+	// Since, if set, enables incremental analysis: only packages affected by changes made since given git ref
+	// (i.e. containing changed files or importing, directly or not, such packages) are analyzed.
+	Since string
+
+	// Previous is a report of earlier full analysis which is merged with results of incremental analysis (see Since):
+	// results for affected packages are replaced, the rest is taken from Previous.
+	Previous *Report
+
+	// Stats enables measurements of the analysis (see Report.Stats).
+	Stats bool
+
+	// Parallelism limits number of packages and call sites analyzed concurrently (0: GOMAXPROCS).
+	Parallelism int
+}
+
+func (cfg Config) groups() []string {
+	if len(cfg.Groups) == 0 {
+		return DefaultGroups
+	}
+	return cfg.Groups
+}
+
+func (cfg Config) includeRoots() []string {
+	if len(cfg.IncludeRoots) == 0 {
+		return DefaultIncludeRoots
+	}
+	return cfg.IncludeRoots
+}
+
+func (cfg Config) excludes() []string {
+	if cfg.Excludes == nil {
+		return DefaultExcludes
+	}
+	return cfg.Excludes
+}
+
+// Analyze loads test packages according to the cfg and returns a Report with API usage of the tests.
+func Analyze(ctx context.Context, cfg Config) (*Report, error) {
+	if cfg.RepoPath == "" {
+		return nil, fmt.Errorf("path to repository is empty")
+	}
+	repoPath, err := filepath.Abs(cfg.RepoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path of %s: %w", cfg.RepoPath, err)
+	}
+	cfg.RepoPath = repoPath
+	if exists, err := checkIfPathExists(cfg.RepoPath); err != nil {
+		return nil, fmt.Errorf("error occurred when checking if path %s exists: %w", cfg.RepoPath, err)
+	} else if !exists {
+		return nil, fmt.Errorf("path %s does not exist", cfg.RepoPath)
+	}
+
+	groups, err := newGroupFilter(cfg.groups())
+	if err != nil {
+		return nil, err
+	}
+	groups.kubernetes = cfg.KubernetesGroups
+
+	sw := newStopwatch()
+	var changed []string
+	if cfg.Since != "" {
+		changed, err = changedFiles(ctx, cfg.RepoPath, cfg.Since)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	astPkgs, err := loadPackages(ctx, cfg, changed)
+	if err != nil {
+		return nil, err
+	}
+	for _, astPkg := range astPkgs {
+		for _, e := range astPkg.Errors {
+			if !strings.Contains(e.Msg, "no Go files") {
+				return nil, fmt.Errorf("failed to load package %s: %v", astPkg.PkgPath, e)
+			}
+		}
+	}
+
+	astPkgs = filter(astPkgs, func(p *packages.Package) bool { return len(p.Errors) == 0 })
+
+	loadTime := sw.lap()
+
+	loader := newPackageLoader(ctx)
+	a := newAnalyzer(cfg, loader, groups)
+
+	// find tests and API call sites of all packages in parallel
+	specs := make([][]*spec, len(astPkgs))
+	calls := make([][]*apiCall, len(astPkgs))
+	parallelize(a.workers(), len(astPkgs), func(idx int) {
+		if ctx.Err() != nil {
+			return
+		}
+		specs[idx] = a.findSpecs(astPkgs[idx], detectFrameworks(astPkgs[idx], cfg.Frameworks))
+		calls[idx] = a.findAPICalls(astPkgs[idx], false)
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// resolve all call sites in parallel, they're independent of each other
+	allCalls := []*apiCall{}
+	for _, c := range calls {
+		allCalls = append(allCalls, c...)
+	}
+	parallelize(a.workers(), len(allCalls), func(idx int) {
+		if ctx.Err() != nil {
+			return
+		}
+		a.resolveAPICall(allCalls[idx])
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	specsByPkg := map[*packages.Package][]*spec{}
+	for idx, astPkg := range astPkgs {
+		specsByPkg[astPkg] = specs[idx]
+		a.attributeLexically(astPkg, specs[idx])
+	}
+
+	analysisTime := sw.lap()
+
+	if cfg.CallGraph != CallGraphNone && len(astPkgs) != 0 {
+		if err := a.attributeReachable(astPkgs, specsByPkg); err != nil {
+			return nil, err
+		}
+	}
+	callGraphTime := sw.lap()
+
+	report := a.finish(astPkgs)
+	if cfg.Since != "" && cfg.Previous != nil {
+		report = mergeReports(cfg.Previous, report, affectedDirs(cfg.RepoPath, astPkgs, changed))
+	}
+
+	if cfg.Stats {
+		report.Stats = &Stats{
+			Packages:         len(astPkgs),
+			OnDemandPackages: loader.loaded(),
+			LoadTime:         loadTime,
+			AnalysisTime:     analysisTime,
+			CallGraphTime:    callGraphTime,
+			TotalTime:        sw.total(),
+		}
+		packages.Visit(astPkgs, nil, func(p *packages.Package) {
+			if len(p.Syntax) != 0 {
+				report.Stats.SourcePackages++
+			}
+		})
+		report.Stats.SourcePackages += report.Stats.OnDemandPackages
+		report.Stats.measureMemory()
+	}
+	return report, nil
+}
@@ -0,0 +1,675 @@
+package apiusage
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+func assert(b bool) {
+	if !b {
+		panic("assertion failed")
+	}
+}
+
+// unsupportedError is raised (as panic) by the investigator when it encounters a construct it can't analyze.
+// It's turned into a diagnostic of the call site being resolved.
+type unsupportedError struct {
+	// node is type of the construct, e.g. *ast.StarExpr
+	node string
+	// pos is position of the construct, which can be in another function or package than the call site
+	pos token.Position
+}
+
+func (e unsupportedError) Error() string {
+	return fmt.Sprintf("unsupported construct: %s", e.node)
+}
+
+// unsupported stops resolution of the call site because the investigator can't analyze the node
+func (i *investigator) unsupported(n ast.Node) {
+	panic(unsupportedError{node: fmt.Sprintf("%T", n), pos: i.pkg.Fset.Position(n.Pos())})
+}
+
+func sanitize(s string) string {
+	return strings.ReplaceAll(s, "\"", "")
+}
+
+func (i *investigator) astPrint(x any) {
+	ast.Print(i.pkg.Fset, x)
+}
+
+// analyzeExpr returns API Groups of GVRs (or groups themselves) the expression evaluates to
+func (i *investigator) analyzeExpr(e ast.Expr) []string {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		// "g"
+		return []string{sanitize(e.Value)}
+	case *ast.Ident:
+		// gvr
+		return i.identValue(e)
+	case *ast.SelectorExpr:
+		if s := fieldSelection(i.pkg.TypesInfo, e); s != nil {
+			// tc.gvr, gvr.Group
+			return i.fieldExpr(e, s)
+		}
+		// pkg.GVR
+		return i.identValue(qualifiedIdent(i.pkg.TypesInfo, e))
+	case *ast.CompositeLit:
+		// GVR{...}, []GVR{...}, map[GVR]*{...}
+		return i.analyzeCompositeLit(e)
+	case *ast.CallExpr:
+		// F(...)
+		return i.analyzeCallResult(e, 0)
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			// &GVR{...}
+			return i.analyzeExpr(e.X)
+		}
+		i.unsupported(e)
+	case *ast.ParenExpr:
+		return i.analyzeExpr(e.X)
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			// "/apis/" + group, prefix + ".openshift.io"
+			return i.concatenation(e)
+		}
+		i.unsupported(e)
+	case *ast.IndexExpr:
+		// gvrs[0], gvrByName["routes"], gvrs[i]
+		return i.rangeElems(e.X, elems{at: i.pkg.TypesInfo.Types[e.Index].Value})
+	default:
+		i.unsupported(e)
+	}
+	return nil
+}
+
+// qualifiedIdent returns identifier declared in another package: "GVR" for `pkg.GVR`
+func qualifiedIdent(info *types.Info, e *ast.SelectorExpr) *ast.Ident {
+	x, ok := e.X.(*ast.Ident)
+	assert(ok)
+	_, ok = info.Uses[x].(*types.PkgName)
+	assert(ok)
+	return e.Sel
+}
+
+// identValue analyzes declaration of the identifier: assignment, or var or const declaration.
+// Values of string constants are taken from type checker, so they're known even without syntax.
+func (i *investigator) identValue(id *ast.Ident) []string {
+	i2, decl, idx := i.declarationOf(id)
+	if c, ok := i.pkg.TypesInfo.ObjectOf(id).(*types.Const); ok && c.Val().Kind() == constant.String {
+		if decl != nil {
+			i2.explain(decl)
+		}
+		return []string{constant.StringVal(c.Val())}
+	}
+
+	if v, ok := i.pkg.TypesInfo.ObjectOf(id).(*types.Var); ok && decl != nil && isCollection(v.Type()) {
+		// gvrs, including GVRs inserted after declaration
+		return i.rangeElems(id, elems{key: isGVRKeyedMap(v.Type())})
+	}
+	if v, ok := i.pkg.TypesInfo.ObjectOf(id).(*types.Var); ok && decl != nil && i2 == i && v.Parent() != v.Pkg().Scope() {
+		// local variable: union of all its definitions reaching the use
+		if i.visiting[v] {
+			// definition carried around a loop reaches itself: `group = group + s`
+			return nil
+		}
+		i.visiting[v] = true
+		defer delete(i.visiting, v)
+		defs := i.reachingDefinitions(id, v)
+		if len(defs) > 1 {
+			i.markMay()
+		}
+		groups := []string{}
+		for _, d := range defs {
+			groups = append(groups, i.definitionValue(d)...)
+		}
+		return groups
+	}
+
+	switch decl := decl.(type) {
+	case *ast.AssignStmt:
+		return i2.assignStmt(decl, idx)
+	case *ast.ValueSpec:
+		return i2.valueSpec(decl, idx)
+	case *ast.Field:
+		return i2.paramValue(decl)
+	case nil:
+		i.unsupported(id)
+	default:
+		i2.unsupported(decl)
+	}
+	return nil
+}
+
+// paramValue analyzes function parameter
+func (i *investigator) paramValue(field *ast.Field) []string {
+	// function arg
+	// need to go up into caller and see all gvrs
+	path, _ := astutil.PathEnclosingInterval(i.root, field.Pos(), field.End())
+	// 0 - *ast.Field (decl), 1 - *ast.FieldList, 2 - *ast.FuncType, 3 - *ast.FuncDecl
+	funcDecl := path[3].(*ast.FuncDecl)
+	_ = funcDecl
+	// TODO: find where function is used and then trace that gvr arg back to declaration
+	return nil
+}
+
+// markMay records that API Groups being resolved depend on control flow
+func (i *investigator) markMay() {
+	if i.may != nil {
+		*i.may = true
+	}
+}
+
+// assignedValue returns expression assigned to idx-th of n variables and index of the result if the expression
+// is a call of function returning many values: `a, b := x, y` (x or y, 0) vs `a, b := f()` (f(), 0 or 1)
+func assignedValue(n int, values []ast.Expr, idx int) (ast.Expr, int) {
+	if len(values) == n {
+		return values[idx], 0
+	}
+	assert(len(values) == 1)
+	return values[0], idx
+}
+
+// assignStmt analyzes value assigned to idx-th variable of the assignment
+func (i *investigator) assignStmt(a *ast.AssignStmt, idx int) []string {
+	i.explain(a)
+	rhs, result := assignedValue(len(a.Lhs), a.Rhs, idx)
+	if rng, ok := rhs.(*ast.UnaryExpr); ok && rng.Op == token.RANGE {
+		// for KEY, VALUE := range X
+		return i.rangeElems(rng.X, elems{key: idx == 0})
+	}
+	return i.analyzeValue(rhs, result)
+}
+
+// valueSpec analyzes value of idx-th variable of the declaration: var gvr = GVR{...}
+func (i *investigator) valueSpec(vs *ast.ValueSpec, idx int) []string {
+	i.explain(vs)
+	if len(vs.Values) == 0 {
+		// var gvr GVR
+		i.unsupported(vs)
+	}
+	value, result := assignedValue(len(vs.Names), vs.Values, idx)
+	return i.analyzeValue(value, result)
+}
+
+// analyzeValue analyzes value assigned to a variable. If value is a call of function returning many values,
+// result is index of the assigned one.
+func (i *investigator) analyzeValue(value ast.Expr, result int) []string {
+	switch value := value.(type) {
+	case *ast.CallExpr:
+		return i.analyzeCallResult(value, result)
+	case *ast.TypeAssertExpr, *ast.IndexExpr:
+		// v, ok := x.(GVR)
+		//    ^^ - second value is bool
+		if result != 0 {
+			return nil
+		}
+	}
+	assert(result == 0)
+	return i.analyzeExpr(value)
+}
+
+// rangeElems returns API Groups of selected keys or values of the collection
+func (i *investigator) rangeElems(x ast.Expr, sel elems) []string {
+	typ := i.pkg.TypesInfo.TypeOf(x).Underlying()
+	if _, ok := typ.(*types.Map); sel.key && !ok {
+		// index of slice or array
+		return nil
+	}
+
+	switch x := x.(type) {
+	case *ast.Ident:
+		// for _, gvr := range gvrs
+		obj := i.pkg.TypesInfo.ObjectOf(x)
+		if _, ok := obj.(*types.Nil); ok {
+			// append(nil, ...)
+			return nil
+		}
+		if i.visiting[obj] {
+			// gvrs = append(gvrs, ...)
+			return nil
+		}
+		i.visiting[obj] = true
+		defer delete(i.visiting, obj)
+
+		groups := []string{}
+		i2, decl, idx := i.declarationOf(x)
+		switch decl := decl.(type) {
+		case *ast.AssignStmt:
+			i2.explain(decl)
+			value, result := assignedValue(len(decl.Lhs), decl.Rhs, idx)
+			groups = i2.rangeValueElems(value, result, sel)
+		case *ast.ValueSpec:
+			i2.explain(decl)
+			if len(decl.Values) != 0 {
+				value, result := assignedValue(len(decl.Names), decl.Values, idx)
+				groups = i2.rangeValueElems(value, result, sel)
+			}
+		case *ast.Field:
+			// named result (or parameter), elements can only be inserted
+		case nil:
+			i.unsupported(x)
+		default:
+			i2.unsupported(decl)
+		}
+		if v, ok := obj.(*types.Var); ok {
+			groups = append(groups, i2.insertedElems(v, sel)...)
+		}
+		return groups
+	case *ast.SelectorExpr:
+		if s := fieldSelection(i.pkg.TypesInfo, x); s != nil {
+			// for _, gvr := range tc.gvrs
+			groups := []string{}
+			i.fieldValues(s, func(i *investigator, value ast.Expr, result int) {
+				groups = append(groups, i.rangeValueElems(value, result, sel)...)
+			})
+			return groups
+		}
+		// for _, gvr := range pkg.GVRs
+		return i.rangeElems(qualifiedIdent(i.pkg.TypesInfo, x), sel)
+	default:
+		return i.rangeValueElems(x, 0, sel)
+	}
+}
+
+// rangeValueElems returns API Groups of selected keys or values of the collection given by its value
+func (i *investigator) rangeValueElems(value ast.Expr, result int, sel elems) []string {
+	switch value := value.(type) {
+	case *ast.CompositeLit:
+		// for _, gvr := range []GVR{...}
+		_, isMap := i.pkg.TypesInfo.TypeOf(value).Underlying().(*types.Map)
+		groups := []string{}
+		next := int64(0)
+		for _, elt := range value.Elts {
+			// index of slice or array element, or key of map element
+			index := constant.MakeInt64(next)
+			kv, isKV := elt.(*ast.KeyValueExpr)
+			if isKV {
+				// []GVR{ 3: gvr } or map[string]GVR{ "routes": gvr }
+				index = i.pkg.TypesInfo.Types[kv.Key].Value
+			}
+			if !isMap {
+				next, _ = constant.Int64Val(constant.ToInt(index))
+				next++
+			}
+			if !sel.selects(index) {
+				continue
+			}
+
+			if !isKV {
+				groups = append(groups, i.analyzeExpr(elt)...)
+				continue
+			}
+			i.explain(kv)
+			if sel.key {
+				groups = append(groups, i.analyzeExpr(kv.Key)...)
+			} else {
+				groups = append(groups, i.analyzeExpr(kv.Value)...)
+			}
+		}
+		return groups
+	case *ast.Ident, *ast.SelectorExpr:
+		// gvrs2 := gvrs
+		return i.rangeElems(value, sel)
+	case *ast.UnaryExpr:
+		if value.Op == token.RANGE {
+			// for _, gvrs := range [][]GVR{...}: elements of nested collections are flattened,
+			// result is the index of the variable (0 for key)
+			return i.rangeElems(value.X, elems{key: result == 0})
+		}
+		// &[]GVR{...}
+		return i.rangeValueElems(value.X, result, sel)
+	case *ast.IndexExpr:
+		// gvrs := nested[0]: elements of nested collections are flattened
+		return i.rangeElems(value.X, elems{at: i.pkg.TypesInfo.Types[value.Index].Value})
+	case *ast.ParenExpr:
+		return i.rangeValueElems(value.X, result, sel)
+	case *ast.CallExpr:
+		if isBuiltin(i.pkg.TypesInfo, value, "append") {
+			// gvrs2 := append(gvrs, gvr)
+			return i.appendElems(value, sel)
+		}
+		if isBuiltin(i.pkg.TypesInfo, value, "make") {
+			// gvrs := make([]GVR, 0), elements are inserted later
+			return nil
+		}
+		if sel.at != nil {
+			// F()[0]
+			return i.callResultElems(value, result, sel)
+		}
+		// collection returned by a function: F() []GVR
+		return i.analyzeValue(value, result)
+	default:
+		// collection returned by a function: F() []GVR
+		return i.analyzeValue(value, result)
+	}
+}
+
+// isFunctionGVRHelper checks for "GVR Helper" which is defined as a function that takes 3 string params
+// and return GVR object: func F(g, v, r) GVR
+func isFunctionGVRHelper(signature *types.Signature) bool {
+	params := signature.Params()
+	funcTakes3Strings := params.Len() == 3 &&
+		params.At(0).Type().String() == "string" &&
+		params.At(1).Type().String() == "string" &&
+		params.At(2).Type().String() == "string"
+
+	results := signature.Results()
+	funcReturnsGVR := results.Len() == 1 &&
+		results.At(0).Type().String() == "k8s.io/apimachinery/pkg/runtime/schema.GroupVersionResource"
+
+	return funcTakes3Strings && funcReturnsGVR
+}
+
+func isTypeGVR(t types.Type) bool {
+	return t != nil && types.TypeString(t, nil) == "k8s.io/apimachinery/pkg/runtime/schema.GroupVersionResource"
+}
+
+// analyzeCompositeLit returns API Groups of GVR literal, or of GVRs in slice or map literal
+func (i *investigator) analyzeCompositeLit(m *ast.CompositeLit) []string {
+	typ := i.pkg.TypesInfo.TypeOf(m)
+	if isTypeGVR(typ) {
+		// GVR{ Group: "g", Version: "v", Resource: "r" } or GVR{ "g", "v", "r" } - only Group is of interest
+		for idx, elt := range m.Elts {
+			switch elt := elt.(type) {
+			case *ast.KeyValueExpr:
+				if key, ok := elt.Key.(*ast.Ident); ok && key.Name == "Group" {
+					i.explain(elt)
+					return i.analyzeExpr(elt.Value)
+				}
+			default:
+				if idx == 0 {
+					return i.analyzeExpr(elt)
+				}
+			}
+		}
+		return nil
+	}
+
+	groups := []string{}
+	switch typ := typ.Underlying().(type) {
+	case *types.Slice, *types.Array:
+		// []GVR{ gvr, GVR{...}, F() }
+		for _, elt := range m.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				// [...]GVR{ 0: gvr }
+				elt = kv.Value
+			}
+			groups = append(groups, i.analyzeExpr(elt)...)
+		}
+	case *types.Map:
+		// map[GVR]*{ GVR: *, ... } or map[*]GVR{ *: GVR, ... }
+		isKeyGVR := isTypeGVR(typ.Key())
+		for _, elt := range m.Elts {
+			kv := elt.(*ast.KeyValueExpr)
+			i.explain(kv)
+			if isKeyGVR {
+				groups = append(groups, i.analyzeExpr(kv.Key)...)
+			} else {
+				groups = append(groups, i.analyzeExpr(kv.Value)...)
+			}
+		}
+	default:
+		i.unsupported(m)
+	}
+	return groups
+}
+
+// analyzeFunction returns API Groups of GVRs returned by the function as result with given index.
+// Results are cached, as the same helpers tend to be used by many tests.
+func (i *investigator) analyzeFunction(fun ast.Node, result int) []string {
+	key := funcSummaryKey{fun: fun, result: result, typeArgs: i.typeArgsKey()}
+	if s, ok := i.summaries.get(key); ok {
+		if i.trace != nil {
+			i.trace.steps = append(i.trace.steps, s.steps...)
+		}
+		if s.may {
+			i.markMay()
+		}
+		return s.groups
+	}
+
+	stepsBefore := 0
+	if i.trace != nil {
+		stepsBefore = len(i.trace.steps)
+	}
+	bodyMay := false
+	i2 := *i
+	i2.may = &bodyMay
+	groups := i2.analyzeFunctionBody(fun, result)
+	if bodyMay {
+		i.markMay()
+	}
+	s := funcSummary{groups: groups, may: bodyMay}
+	if i.trace != nil {
+		s.steps = append([]ResolutionStep{}, i.trace.steps[stepsBefore:]...)
+	}
+	i.summaries.put(key, s)
+	return groups
+}
+
+func (i *investigator) analyzeFunctionBody(fun ast.Node, result int) []string {
+	return i.returnedGroups(fun, result, (*investigator).analyzeValue)
+}
+
+// returnedGroups returns union of API Groups of values returned by all return statements of the function
+// (*ast.FuncDecl or *ast.FuncLit) as result with given index, as analyzed by f. Returns of nested function literals
+// are skipped. Functions terminated by panic or Fail don't need a return statement. API Groups are marked as "may"
+// if more than one return statement contributes them. Recursive call of the function contributes nothing,
+// as its values are returned by other return statements.
+func (i *investigator) returnedGroups(fun ast.Node, result int, f func(i *investigator, value ast.Expr, result int) []string) []string {
+	key := funcSummaryKey{fun: fun, result: result, typeArgs: i.typeArgsKey()}
+	if i.analyzing[key] {
+		i.markMay()
+		return nil
+	}
+	i.analyzing[key] = true
+	defer delete(i.analyzing, key)
+
+	i.explain(fun)
+	typ, body := i.funcParts(fun)
+
+	groups := []string{}
+	contributing := 0
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			i.explain(n)
+			var value ast.Expr
+			valueResult := 0
+			if len(n.Results) == 0 {
+				// bare return: value of the named result
+				value = i.namedResult(typ, result)
+			} else {
+				// return x, y or return F() where F returns many values
+				value, valueResult = assignedValue(typ.Results.NumFields(), n.Results, result)
+			}
+			if g := f(i, value, valueResult); len(g) != 0 {
+				groups = append(groups, g...)
+				contributing++
+			}
+		}
+		return true
+	})
+	if contributing > 1 {
+		i.markMay()
+	}
+	return groups
+}
+
+// namedResult returns name of idx-th result of the function
+func (i *investigator) namedResult(typ *ast.FuncType, idx int) *ast.Ident {
+	for _, field := range typ.Results.List {
+		if idx < len(field.Names) {
+			return field.Names[idx]
+		}
+		idx -= len(field.Names)
+	}
+	i.unsupported(typ)
+	return nil
+}
+
+// callResultElems returns API Groups of selected elements of a collection returned by the call as result with given index.
+// Unlike analyzeCallResult results aren't cached, as they depend on the selection.
+func (i *investigator) callResultElems(ce *ast.CallExpr, result int, sel elems) []string {
+	i.explain(ce)
+	i2, fd := i.funcDeclOf(ce)
+	return i2.returnedGroups(fd, result, func(i *investigator, value ast.Expr, result int) []string {
+		return i.rangeValueElems(value, result, sel)
+	})
+}
+
+// funcDeclOf returns declaration of the called function. If function resides in another package,
+// returned investigator operates on that different pkg.
+func (i *investigator) funcDeclOf(ce *ast.CallExpr) (*investigator, *ast.FuncDecl) {
+	funId := calleeIdent(ce)
+	if funId == nil {
+		// e.g. func literal or method value
+		i.unsupported(ce.Fun)
+	}
+	f, ok := i.pkg.TypesInfo.ObjectOf(funId).(*types.Func)
+	assert(ok)
+	return i.funcDecl(f)
+}
+
+// funcDecl returns declaration of the function or method, with investigator operating on its package
+func (i *investigator) funcDecl(f *types.Func) (*investigator, *ast.FuncDecl) {
+	i2, decl, _ := i.declarationOfObject(f)
+	fd, ok := decl.(*ast.FuncDecl)
+	assert(ok)
+	return i2, fd
+}
+
+// analyzeCallResult returns API Groups of GVRs returned by the call as result with given index
+func (i *investigator) analyzeCallResult(ce *ast.CallExpr, result int) []string {
+	if isBuiltin(i.pkg.TypesInfo, ce, "append") {
+		// append(gvrs, gvr)
+		return i.appendElems(ce, elems{})
+	}
+	if isPathJoin(i.pkg.TypesInfo, ce) {
+		// path.Join("/apis", group, version)
+		i.explain(ce)
+		return i.joinedPaths(ce)
+	}
+	i.explain(ce)
+	if sig, ok := i.pkg.TypesInfo.TypeOf(ce.Fun).(*types.Signature); ok && isFunctionGVRHelper(sig) {
+		// just take first arg which is assumed to be api group
+		// F( "g", ... )
+		return i.analyzeExpr(ce.Args[0])
+	}
+
+	// not a "helper function F(g,v,r) GVR" but a function that returns GVR in some form like []GVR, map[GVR]* or map[*]GVR
+	if fl, ok := ast.Unparen(ce.Fun).(*ast.FuncLit); ok {
+		// func() GVR { ... }()
+		return i.analyzeFunction(fl, result)
+	}
+	funId := calleeIdent(ce)
+	if funId == nil {
+		i.unsupported(ce.Fun)
+	}
+	switch obj := i.pkg.TypesInfo.Uses[funId].(type) {
+	case *types.Var:
+		// mk := func() GVR { ... }; mk()
+		return i.funcVarResult(funId, obj, result)
+	case *types.Func:
+		return i.callFunction(ce.Fun, funId, obj, result)
+	default:
+		i.unsupported(funId)
+	}
+	return nil
+}
+
+type investigator struct {
+	pkg  *packages.Package
+	root *ast.File
+	// trace, if not nil, collects steps taken to resolve API Groups
+	trace *trace
+	// summaries, if not nil, caches results of analyzeFunction
+	summaries *summaryCache
+	// loader loads syntax of imported packages when their functions need to be analyzed
+	loader *packageLoader
+	// decls maps identifiers to their declarations
+	decls *declarations
+	// may, if not nil, is set when more than one definition of a variable reaches its use,
+	// i.e. only some of the resolved API Groups may be used at a time
+	may *bool
+	// typeArgs binds type parameters of analyzed generic function (by name) to type arguments
+	typeArgs map[string]types.Type
+	// visiting holds variables being analyzed, to stop at `gvrs = append(gvrs, ...)` or `group = group + s`
+	visiting map[types.Object]bool
+	// analyzing holds results of functions being analyzed, to stop at recursive calls
+	analyzing map[funcSummaryKey]bool
+	// resources, if not nil, collects resources of REST paths accessed by the call site
+	resources *[]Resource
+	// notAPICall is set when all REST paths of the call site are outside of API Groups
+	notAPICall bool
+}
+
+// analyzeAPICall expects an *ast.CallExpr that is confirmed to be API call of the callee (see apiCallMatcher),
+// e.g. k8s.io/client-go/dynamic.Interface.Resource() call. It returns all API Groups used in that function call
+func (i *investigator) analyzeAPICall(call *ast.CallExpr, callee *apiCallee) []string {
+	switch callee.kind {
+	case restPathCall:
+		// AbsPath("/apis/config.openshift.io/v1/clusteroperators")
+		return i.restPathCall(call)
+	case rawCLICall:
+		// oc.Run("get").Args("--raw", "/apis/config.openshift.io/v1/clusteroperators")
+		return i.rawCLICall(call)
+	case typedCall:
+		// oc.AdminKubeClient().CoreV1().Pods(ns).List(...)
+		return i.typedCall(call, callee)
+	}
+	i.explain(call)
+	// Resource(GroupVersionResource{...}), Resource(gvr), Resource(F(...)), Resource(pkg.GVR), dynamiclister.New(indexer, gvr)
+	arg := gvrArg(i.pkg.TypesInfo, call)
+	if arg == nil {
+		// e.g. Resource(gvrs...)
+		i.unsupported(call)
+	}
+	return i.analyzeExpr(arg)
+}
+
+// errNotAPICall is returned by resolve if the call turns out not to be an API call, e.g. AbsPath("/healthz/" + name)
+var errNotAPICall = errors.New("not an API call")
+
+// resolve runs analyzeAPICall turning panics of unsupported cases into an error
+func (i *investigator) resolve(call *ast.CallExpr, callee *apiCallee) (groups []string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(loadError); ok {
+				err = e.error
+				return
+			}
+			if e, ok := r.(unsupportedError); ok {
+				err = e
+				return
+			}
+			err = fmt.Errorf("unsupported construct: %v", r)
+		}
+	}()
+	if i.visiting == nil {
+		i.visiting = map[types.Object]bool{}
+	}
+	if i.analyzing == nil {
+		i.analyzing = map[funcSummaryKey]bool{}
+	}
+	groups = filter(i.analyzeAPICall(call, callee), func(g string) bool {
+		return !strings.Contains(g, unknownValue)
+	})
+	if len(groups) == 0 {
+		if i.notAPICall {
+			return nil, errNotAPICall
+		}
+		return nil, fmt.Errorf("API Group could not be resolved")
+	}
+	return uniqueSorted(groups), nil
+}
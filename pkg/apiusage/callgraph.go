@@ -0,0 +1,252 @@
+package apiusage
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/callgraph/vta"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// CallGraphAlgorithm selects how call graph is constructed.
+type CallGraphAlgorithm string
+
+const (
+	// CallGraphNone disables call graph: only API call sites lexically inside tests' bodies are attributed.
+	CallGraphNone CallGraphAlgorithm = ""
+	// CallGraphCHA uses Class Hierarchy Analysis: fast, but imprecise for dynamic calls.
+	CallGraphCHA CallGraphAlgorithm = "cha"
+	// CallGraphVTA uses Variable Type Analysis (refining CHA): slower, but more precise for dynamic calls.
+	CallGraphVTA CallGraphAlgorithm = "vta"
+)
+
+// callGraph is a call graph built for packages sharing single token.FileSet
+type callGraph struct {
+	fset  *token.FileSet
+	prog  *ssa.Program
+	graph *callgraph.Graph
+	// funcsBySyntax maps *ast.FuncDecl and *ast.FuncLit to SSA functions (many, in case of generic functions)
+	funcsBySyntax map[ast.Node][]*ssa.Function
+	// pkgs maps type-checked packages to loaded packages, so syntax of reached functions can be inspected
+	pkgs map[*types.Package]*packages.Package
+}
+
+func buildCallGraph(pkgs []*packages.Package, algo CallGraphAlgorithm) (*callGraph, error) {
+	prog, _ := ssautil.AllPackages(pkgs, ssa.InstantiateGenerics)
+	prog.Build()
+
+	cg := &callGraph{
+		fset:          pkgs[0].Fset,
+		prog:          prog,
+		funcsBySyntax: map[ast.Node][]*ssa.Function{},
+		pkgs:          map[*types.Package]*packages.Package{},
+	}
+
+	allFuncs := ssautil.AllFunctions(prog)
+	switch algo {
+	case CallGraphCHA:
+		cg.graph = cha.CallGraph(prog)
+	case CallGraphVTA:
+		cg.graph = vta.CallGraph(allFuncs, cha.CallGraph(prog))
+	default:
+		return nil, fmt.Errorf("unknown call graph algorithm: %q", algo)
+	}
+	cg.graph.DeleteSyntheticNodes()
+
+	for fn := range allFuncs {
+		if syntax := fn.Syntax(); syntax != nil {
+			cg.funcsBySyntax[syntax] = append(cg.funcsBySyntax[syntax], fn)
+		}
+	}
+	packages.Visit(pkgs, nil, func(p *packages.Package) {
+		cg.pkgs[p.Types] = p
+	})
+
+	return cg, nil
+}
+
+// isAnalyzable checks if function belongs to one of analyzed modules (as opposed to dependencies like client-go).
+// Only such functions are traversed when looking for reachable API call sites.
+func (cg *callGraph) isAnalyzable(fn *ssa.Function) (*packages.Package, bool) {
+	if fn == nil || fn.Pkg == nil || fn.Syntax() == nil {
+		return nil, false
+	}
+	pkg, ok := cg.pkgs[fn.Pkg.Pkg]
+	if !ok || pkg.Module == nil || !pkg.Module.Main {
+		return nil, false
+	}
+	return pkg, true
+}
+
+// specBodies returns functions executed as part of the test: body of the test itself
+// and, for Ginkgo, bodies of enclosing containers
+func (cg *callGraph) specBodies(info *types.Info, s *spec) []*ssa.Function {
+	funcs := []*ssa.Function{}
+	for _, n := range append(append([]ast.Node{}, s.containers...), s.node) {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			funcs = append(funcs, cg.funcsBySyntax[n]...)
+		case *ast.CallExpr:
+			// g.It("name", func() {...}), t.Run("name", func(t *testing.T) {...})
+			for _, arg := range n.Args {
+				switch arg := arg.(type) {
+				case *ast.FuncLit:
+					funcs = append(funcs, cg.funcsBySyntax[arg]...)
+				case *ast.Ident:
+					if f, ok := info.Uses[arg].(*types.Func); ok {
+						if fn := cg.prog.FuncValue(f); fn != nil {
+							funcs = append(funcs, fn)
+						}
+					}
+				}
+			}
+		}
+	}
+	return funcs
+}
+
+// isInside checks if node x is within any of the nodes
+func isInside(x ast.Node, nodes ...ast.Node) bool {
+	for _, n := range nodes {
+		if n.Pos() <= x.Pos() && x.End() <= n.End() {
+			return true
+		}
+	}
+	return false
+}
+
+// attributeReachable links tests to API call sites in functions reachable from tests' bodies according to call graph.
+func (a *analyzer) attributeReachable(pkgs []*packages.Package, specsByPkg map[*packages.Package][]*spec) error {
+	// SSA program can only be built from packages sharing FileSet, i.e. loaded together
+	byFset := map[*token.FileSet][]*packages.Package{}
+	fsets := []*token.FileSet{}
+	for _, pkg := range pkgs {
+		if _, ok := byFset[pkg.Fset]; !ok {
+			fsets = append(fsets, pkg.Fset)
+		}
+		byFset[pkg.Fset] = append(byFset[pkg.Fset], pkg)
+	}
+
+	for _, fset := range fsets {
+		cg, err := buildCallGraph(byFset[fset], a.cfg.CallGraph)
+		if err != nil {
+			return err
+		}
+		type job struct {
+			pkg    *packages.Package
+			spec   *spec
+			bodies []*ssa.Function
+		}
+		jobs := []job{}
+		for _, pkg := range byFset[fset] {
+			for _, s := range specsByPkg[pkg] {
+				// SSA program is queried before going parallel, the call graph itself is only read afterwards
+				jobs = append(jobs, job{pkg: pkg, spec: s, bodies: cg.specBodies(pkg.TypesInfo, s)})
+			}
+		}
+		parallelize(a.workers(), len(jobs), func(idx int) {
+			a.attributeReachableFromSpec(cg, jobs[idx].pkg, jobs[idx].spec, jobs[idx].bodies)
+		})
+	}
+	return nil
+}
+
+func (a *analyzer) attributeReachableFromSpec(cg *callGraph, pkg *packages.Package, s *spec, bodies []*ssa.Function) {
+	type visit struct {
+		depth int
+		// edge used to reach the function, nil for roots and closures
+		via *callgraph.Edge
+		// parent is a function creating the closure, if it was reached by its creation rather than a call:
+		// closures passed to wait.Poll are called only by functions outside of analyzed modules
+		parent *ssa.Function
+	}
+	visited := map[*ssa.Function]visit{}
+	queue := []*ssa.Function{}
+	for _, fn := range bodies {
+		if _, ok := visited[fn]; !ok {
+			visited[fn] = visit{depth: 0}
+			queue = append(queue, fn)
+		}
+	}
+
+	// path returns calls leading to the function. Closure reached by its creation is linked to its parent
+	// by an edge positioned at the function literal.
+	path := func(fn *ssa.Function) []CallEdge {
+		edges := []CallEdge{}
+		for {
+			v := visited[fn]
+			var caller *ssa.Function
+			var edge CallEdge
+			switch {
+			case v.via != nil:
+				caller = v.via.Caller.Func
+				edge = CallEdge{Caller: caller.String(), Callee: fn.String(), Position: cg.fset.Position(v.via.Pos())}
+			case v.parent != nil:
+				caller = v.parent
+				edge = CallEdge{Caller: caller.String(), Callee: fn.String(), Position: cg.fset.Position(fn.Pos())}
+			default:
+				return edges
+			}
+			edges = append([]CallEdge{edge}, edges...)
+			fn = caller
+		}
+	}
+
+	lexicalScope := append(append([]ast.Node{}, s.containers...), s.node)
+	done := map[*apiCall]bool{}
+	for len(queue) != 0 {
+		fn := queue[0]
+		queue = queue[1:]
+
+		fnPkg, ok := cg.isAnalyzable(fn)
+		if !ok {
+			continue
+		}
+
+		// call sites lexically inside the test were already attributed
+		if visited[fn].depth > 0 && !isInside(fn.Syntax(), lexicalScope...) {
+			for _, c := range a.findAPICalls(fnPkg, true) {
+				if c.enclosingFunc() != fn.Syntax() || done[c] {
+					continue
+				}
+				done[c] = true
+				cs := c.site
+				cs.Via = path(fn)
+				a.attribute(s, pkg, c, cs)
+			}
+		}
+
+		if a.cfg.CallGraphDepth > 0 && visited[fn].depth >= a.cfg.CallGraphDepth {
+			continue
+		}
+		if visited[fn].depth > 0 {
+			// closures created by reached function are assumed to be called, e.g. by wait.Poll.
+			// Closures of test's body and containers are not: they're bodies of other tests.
+			for _, anon := range fn.AnonFuncs {
+				if _, ok := visited[anon]; ok {
+					continue
+				}
+				visited[anon] = visit{depth: visited[fn].depth + 1, parent: fn}
+				queue = append(queue, anon)
+			}
+		}
+		node := cg.graph.Nodes[fn]
+		if node == nil {
+			continue
+		}
+		for _, e := range node.Out {
+			// visited check also protects against cycles in the call graph
+			if _, ok := visited[e.Callee.Func]; ok {
+				continue
+			}
+			visited[e.Callee.Func] = visit{depth: visited[fn].depth + 1, via: e}
+			queue = append(queue, e.Callee.Func)
+		}
+	}
+}
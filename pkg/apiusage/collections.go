@@ -0,0 +1,137 @@
+package apiusage
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+)
+
+// elems selects elements of a collection
+type elems struct {
+	// key selects keys of map instead of values
+	key bool
+	// at, if not nil, is a constant index (of slice or array) or key (of map) of the only element of interest:
+	// gvrs[0], gvrByName["routes"]
+	at constant.Value
+}
+
+// selects returns false if the element stored under the index (or key) is known not to be selected.
+// Elements under unknown index are always selected.
+func (sel elems) selects(index constant.Value) bool {
+	if sel.at == nil || index == nil || sel.at.Kind() != index.Kind() {
+		return true
+	}
+	return constant.Compare(sel.at, token.EQL, index)
+}
+
+// isCollection returns true for slices, arrays and maps
+func isCollection(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Slice, *types.Array, *types.Map:
+		return true
+	}
+	return false
+}
+
+// isGVRKeyedMap returns true for map[GVR]*
+func isGVRKeyedMap(t types.Type) bool {
+	m, ok := t.Underlying().(*types.Map)
+	return ok && isTypeGVR(m.Key())
+}
+
+// isBuiltin checks if the call is a call of builtin function with the name, e.g. append
+func isBuiltin(info *types.Info, ce *ast.CallExpr, name string) bool {
+	id, ok := ast.Unparen(ce.Fun).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	b, ok := info.Uses[id].(*types.Builtin)
+	return ok && b.Name() == name
+}
+
+// appendElems returns API Groups of elements of slice built by append(s, x, y) or append(s, xs...).
+// Indexes of appended elements aren't tracked, so all elements are selected.
+func (i *investigator) appendElems(ce *ast.CallExpr, sel elems) []string {
+	i.explain(ce)
+	if sel.key {
+		// index of slice
+		return nil
+	}
+	groups := i.rangeElems(ce.Args[0], elems{})
+	if ce.Ellipsis.IsValid() {
+		return append(groups, i.rangeElems(ce.Args[1], elems{})...)
+	}
+	for _, arg := range ce.Args[1:] {
+		groups = append(groups, i.analyzeExpr(arg)...)
+	}
+	return groups
+}
+
+// insertedElems returns API Groups of selected keys or values inserted to the collection variable
+// after its declaration: `gvrs = append(gvrs, gvr)`, `m[gvr] = x`, `copy(gvrs, other)`.
+// Insertions are looked for in the function declaring local variable, or in the whole package
+// for package-level variable, regardless of control flow.
+func (i *investigator) insertedElems(v *types.Var, sel elems) []string {
+	// roots to look for insertions in, with files containing them
+	type root struct {
+		node ast.Node
+		file *ast.File
+	}
+	roots := []root{}
+	if scope := v.Pkg().Scope(); scope.Lookup(v.Name()) == v {
+		// object of the same variable as seen by the package, which might be loaded on demand
+		if v, _ = i.pkg.Types.Scope().Lookup(v.Name()).(*types.Var); v == nil {
+			return nil
+		}
+		for _, file := range i.pkg.Syntax {
+			roots = append(roots, root{node: file, file: file})
+		}
+	} else {
+		_, body := enclosingFunc(i.root, v.Pos())
+		if body == nil {
+			return nil
+		}
+		roots = append(roots, root{node: body, file: i.root})
+	}
+
+	info := i.pkg.TypesInfo
+	is := func(e ast.Expr) bool {
+		id, ok := ast.Unparen(e).(*ast.Ident)
+		return ok && info.Uses[id] == v
+	}
+	groups := []string{}
+	for _, r := range roots {
+		i2 := i.in(i.pkg, r.file)
+		ast.Inspect(r.node, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				for idx, lhs := range n.Lhs {
+					if is(lhs) {
+						// gvrs = append(gvrs, gvr)
+						i2.explain(n)
+						value, result := assignedValue(len(n.Lhs), n.Rhs, idx)
+						groups = append(groups, i2.rangeValueElems(value, result, sel)...)
+					} else if ix, ok := lhs.(*ast.IndexExpr); ok && is(ix.X) && sel.selects(info.Types[ix.Index].Value) {
+						// m[gvr] = x or gvrs[0] = gvr
+						i2.explain(n)
+						if sel.key {
+							groups = append(groups, i2.analyzeExpr(ix.Index)...)
+						} else {
+							value, result := assignedValue(len(n.Lhs), n.Rhs, idx)
+							groups = append(groups, i2.analyzeValue(value, result)...)
+						}
+					}
+				}
+			case *ast.CallExpr:
+				if isBuiltin(info, n, "copy") && is(n.Args[0]) {
+					// copy(gvrs, other)
+					i2.explain(n)
+					groups = append(groups, i2.rangeElems(n.Args[1], sel)...)
+				}
+			}
+			return true
+		})
+	}
+	return groups
+}
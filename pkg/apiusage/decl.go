@@ -0,0 +1,214 @@
+package apiusage
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sync"
+
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/packages"
+)
+
+// declaration is a syntax node declaring an identifier: *ast.AssignStmt, *ast.ValueSpec, *ast.Field,
+// *ast.FuncDecl or *ast.TypeSpec. Variables declared by range statement get *ast.AssignStmt
+// with `KEY, VALUE := range X` (the same way go/parser did for deprecated ast.Object).
+type declaration struct {
+	file *ast.File
+	node ast.Node
+	// index of the identifier among identifiers declared by the node, e.g. 1 for b in `a, b := f()`
+	index int
+}
+
+// declKey is a position of declared identifier. Positions, unlike types.Object, can be matched
+// between package loaded from export data and the same package loaded from source on demand.
+type declKey struct {
+	filename     string
+	line, column int
+}
+
+// declLineKey identifies declared identifier by its name and line. It's used if position doesn't match exactly:
+// export data doesn't always keep position of the identifier itself (e.g. functions point to "func" keyword).
+type declLineKey struct {
+	filename string
+	line     int
+	name     string
+}
+
+func newDeclKey(pos token.Position) declKey {
+	return declKey{filename: pos.Filename, line: pos.Line, column: pos.Column}
+}
+
+// declIndex maps identifiers of a package to their declarations
+type declIndex struct {
+	byPos  map[declKey]declaration
+	byLine map[declLineKey]declaration
+}
+
+func (idx *declIndex) lookup(pos token.Position, name string) (declaration, bool) {
+	if decl, ok := idx.byPos[newDeclKey(pos)]; ok {
+		return decl, true
+	}
+	decl, ok := idx.byLine[declLineKey{filename: pos.Filename, line: pos.Line, name: name}]
+	return decl, ok
+}
+
+// declarations indexes declarations of identifiers of packages, so types.Object can be traced back to its syntax
+// regardless of file or package it's declared in. Index of a package is built on first use.
+// It's safe for concurrent use, and nil declarations is valid (indexes aren't cached).
+type declarations struct {
+	mu    sync.Mutex
+	byPkg map[*packages.Package]*pkgDeclarations
+}
+
+type pkgDeclarations struct {
+	once  sync.Once
+	index *declIndex
+}
+
+func newDeclarations() *declarations {
+	return &declarations{byPkg: map[*packages.Package]*pkgDeclarations{}}
+}
+
+// lookup returns declaration of an identifier with the name declared at the position in the package
+func (d *declarations) lookup(pkg *packages.Package, pos token.Position, name string) (declaration, bool) {
+	if d == nil {
+		return indexDeclarations(pkg).lookup(pos, name)
+	}
+
+	d.mu.Lock()
+	pd, ok := d.byPkg[pkg]
+	if !ok {
+		pd = &pkgDeclarations{}
+		d.byPkg[pkg] = pd
+	}
+	d.mu.Unlock()
+
+	pd.once.Do(func() { pd.index = indexDeclarations(pkg) })
+	return pd.index.lookup(pos, name)
+}
+
+func indexDeclarations(pkg *packages.Package) *declIndex {
+	idx := &declIndex{byPos: map[declKey]declaration{}, byLine: map[declLineKey]declaration{}}
+	ranges := map[*ast.RangeStmt]*ast.AssignStmt{}
+	for _, file := range pkg.Syntax {
+		inspector.New([]*ast.File{file}).WithStack(
+			[]ast.Node{&ast.Ident{}},
+			func(n ast.Node, push bool, stack []ast.Node) (proceed bool) {
+				id := n.(*ast.Ident)
+				if !push || len(stack) < 2 || pkg.TypesInfo.Defs[id] == nil {
+					return true
+				}
+				decl := declaration{file: file}
+				switch parent := stack[len(stack)-2].(type) {
+				case *ast.AssignStmt:
+					decl.node, decl.index = parent, getIndex(parent.Lhs, func(e ast.Expr) bool { return e == id })
+				case *ast.ValueSpec:
+					decl.node, decl.index = parent, getIndex(parent.Names, func(n *ast.Ident) bool { return n == id })
+				case *ast.Field:
+					decl.node, decl.index = parent, getIndex(parent.Names, func(n *ast.Ident) bool { return n == id })
+				case *ast.FuncDecl, *ast.TypeSpec:
+					decl.node = parent
+				case *ast.RangeStmt:
+					if _, ok := ranges[parent]; !ok {
+						ranges[parent] = rangeAssignStmt(parent)
+					}
+					decl.node, decl.index = ranges[parent], getIndex(ranges[parent].Lhs, func(e ast.Expr) bool { return e == id })
+				default:
+					return true
+				}
+				pos := pkg.Fset.Position(id.Pos())
+				idx.byPos[newDeclKey(pos)] = decl
+				idx.byLine[declLineKey{filename: pos.Filename, line: pos.Line, name: id.Name}] = decl
+				return true
+			},
+		)
+	}
+	return idx
+}
+
+// rangeAssignStmt represents `for KEY, VALUE := range X` as `KEY, VALUE := range X`
+func rangeAssignStmt(rs *ast.RangeStmt) *ast.AssignStmt {
+	lhs := []ast.Expr{}
+	for _, e := range []ast.Expr{rs.Key, rs.Value} {
+		if e != nil {
+			lhs = append(lhs, e)
+		}
+	}
+	return &ast.AssignStmt{
+		Lhs:    lhs,
+		TokPos: rs.TokPos,
+		Tok:    rs.Tok,
+		Rhs:    []ast.Expr{&ast.UnaryExpr{OpPos: rs.Range, Op: token.RANGE, X: rs.X}},
+	}
+}
+
+// declarationOf returns syntax declaring the identifier (see declaration) or nil if it's not known,
+// e.g. for predeclared identifiers, together with index of the identifier among identifiers declared by the node.
+// Returned investigator operates on the package and file containing the declaration,
+// which is where the declaration needs to be analyzed.
+func (i *investigator) declarationOf(id *ast.Ident) (*investigator, ast.Node, int) {
+	return i.declarationOfObject(i.pkg.TypesInfo.ObjectOf(id))
+}
+
+// declarationOfObject is like declarationOf, for an object declared in the package or in its imports
+func (i *investigator) declarationOfObject(obj types.Object) (*investigator, ast.Node, int) {
+	if f, ok := obj.(*types.Func); ok {
+		// method of instantiated generic type
+		obj = f.Origin()
+	}
+	if obj == nil || obj.Pkg() == nil || !obj.Pos().IsValid() {
+		return i, nil, 0
+	}
+
+	declPkg := i.pkg
+	if obj.Pkg() != i.pkg.Types {
+		declPkg = i.packageOf(obj.Pkg().Path())
+	}
+	decl, ok := i.decls.lookup(declPkg, i.pkg.Fset.Position(obj.Pos()), obj.Name())
+	if !ok {
+		return i, nil, 0
+	}
+	return i.in(declPkg, decl.file), decl.node, decl.index
+}
+
+// localDeclarationOf returns syntax declaring the identifier, or nil if it's not declared in the package
+func localDeclarationOf(pkg *packages.Package, decls *declarations, id *ast.Ident) ast.Node {
+	obj := pkg.TypesInfo.ObjectOf(id)
+	if obj == nil || obj.Pkg() != pkg.Types {
+		return nil
+	}
+	decl, _ := decls.lookup(pkg, pkg.Fset.Position(obj.Pos()), obj.Name())
+	return decl.node
+}
+
+// packageOf returns imported package, with syntax loaded
+func (i *investigator) packageOf(path string) *packages.Package {
+	var pkg *packages.Package
+	packages.Visit([]*packages.Package{i.pkg}, func(p *packages.Package) bool {
+		if p.PkgPath == path {
+			pkg = p
+		}
+		return pkg == nil
+	}, nil)
+	if pkg == nil {
+		// not reachable through Imports, if dependency was loaded from export data
+		pkg = &packages.Package{PkgPath: path}
+	}
+	pkg, err := i.loader.withSyntax(i.pkg, pkg)
+	if err != nil {
+		panic(loadError{err})
+	}
+	return pkg
+}
+
+// in returns investigator for the file of the package, sharing state with i
+func (i *investigator) in(pkg *packages.Package, file *ast.File) *investigator {
+	if pkg == i.pkg && file == i.root {
+		return i
+	}
+	i2 := *i
+	i2.pkg = pkg
+	i2.root = file
+	return &i2
+}
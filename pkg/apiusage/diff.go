@@ -0,0 +1,152 @@
+package apiusage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// LoadReport reads Report previously saved as JSON.
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+	r := &Report{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// TestDiff describes change of API usage of a single test.
+type TestDiff struct {
+	Name    string `json:"name"`
+	Package string `json:"package"`
+	// Status is one of: "added" (test is present only in new report), "removed" (only in old report),
+	// "changed" (present in both reports).
+	Status        string   `json:"status"`
+	AddedGroups   []string `json:"addedGroups,omitempty"`
+	RemovedGroups []string `json:"removedGroups,omitempty"`
+}
+
+// ReportDiff is a difference between two reports.
+type ReportDiff struct {
+	// Tests lists tests whose API usage changed.
+	Tests []TestDiff `json:"tests"`
+	// AddedGroups lists API Groups used by any test in new report, but not in the old one.
+	AddedGroups []string `json:"addedGroups"`
+	// RemovedGroups lists API Groups used by any test in old report, but not in the new one.
+	RemovedGroups []string `json:"removedGroups"`
+	// NewUnresolved lists problems present only in the new report.
+	// Diagnostics are matched by file and message, so they're not reported again just because lines shifted.
+	NewUnresolved []Diagnostic `json:"newUnresolved"`
+}
+
+// Empty returns true if there are no differences.
+func (d *ReportDiff) Empty() bool {
+	return len(d.Tests) == 0 && len(d.AddedGroups) == 0 && len(d.RemovedGroups) == 0 && len(d.NewUnresolved) == 0
+}
+
+// DiffReports compares two reports, e.g. of main branch and a PR.
+func DiffReports(oldR, newR *Report) *ReportDiff {
+	d := &ReportDiff{Tests: []TestDiff{}, NewUnresolved: []Diagnostic{}}
+
+	// tests of different packages (go tests, Ginkgo specs with the same text) may share the name
+	key := func(t TestUsage) string { return t.Package + "\x00" + t.Name }
+	oldTests := map[string]TestUsage{}
+	for _, t := range oldR.Tests {
+		oldTests[key(t)] = t
+	}
+	newTests := map[string]TestUsage{}
+	for _, t := range newR.Tests {
+		newTests[key(t)] = t
+	}
+
+	for _, nt := range newR.Tests {
+		ot, ok := oldTests[key(nt)]
+		if !ok {
+			if len(nt.APIGroups) != 0 {
+				d.Tests = append(d.Tests, TestDiff{Name: nt.Name, Package: nt.Package, Status: "added", AddedGroups: nt.APIGroups})
+			}
+			continue
+		}
+		added, removed := diffSets(ot.APIGroups, nt.APIGroups)
+		if len(added) != 0 || len(removed) != 0 {
+			d.Tests = append(d.Tests, TestDiff{Name: nt.Name, Package: nt.Package, Status: "changed", AddedGroups: added, RemovedGroups: removed})
+		}
+	}
+	for _, ot := range oldR.Tests {
+		if _, ok := newTests[key(ot)]; !ok && len(ot.APIGroups) != 0 {
+			d.Tests = append(d.Tests, TestDiff{Name: ot.Name, Package: ot.Package, Status: "removed", RemovedGroups: ot.APIGroups})
+		}
+	}
+	sort.SliceStable(d.Tests, func(i, j int) bool {
+		if d.Tests[i].Name != d.Tests[j].Name {
+			return d.Tests[i].Name < d.Tests[j].Name
+		}
+		return d.Tests[i].Package < d.Tests[j].Package
+	})
+
+	d.AddedGroups, d.RemovedGroups = diffSets(allGroups(oldR), allGroups(newR))
+
+	// match diagnostics by file and message: if there are more of them in the new report,
+	// the ones on lines not present in the old report are considered new
+	type diagKey struct{ file, msg string }
+	oldDiags := map[diagKey][]Diagnostic{}
+	for _, diag := range oldR.Diagnostics {
+		k := diagKey{diag.Position.Filename, diag.Message}
+		oldDiags[k] = append(oldDiags[k], diag)
+	}
+	newDiags := map[diagKey][]Diagnostic{}
+	for _, diag := range newR.Diagnostics {
+		k := diagKey{diag.Position.Filename, diag.Message}
+		newDiags[k] = append(newDiags[k], diag)
+	}
+	for k, nds := range newDiags {
+		ods := oldDiags[k]
+		if len(nds) <= len(ods) {
+			continue
+		}
+		for _, nd := range nds {
+			if getIndex(ods, func(od Diagnostic) bool { return od.Position.Line == nd.Position.Line }) == -1 {
+				d.NewUnresolved = append(d.NewUnresolved, nd)
+			}
+		}
+	}
+	sort.SliceStable(d.NewUnresolved, func(i, j int) bool {
+		return lessPosition(d.NewUnresolved[i].Position, d.NewUnresolved[j].Position)
+	})
+
+	return d
+}
+
+func allGroups(r *Report) []string {
+	groups := []string{}
+	for _, t := range r.Tests {
+		groups = append(groups, t.APIGroups...)
+	}
+	return uniqueSorted(groups)
+}
+
+// diffSets returns elements present only in b (added) and only in a (removed)
+func diffSets(a, b []string) (added, removed []string) {
+	inA := map[string]bool{}
+	for _, x := range a {
+		inA[x] = true
+	}
+	inB := map[string]bool{}
+	for _, x := range b {
+		inB[x] = true
+		if !inA[x] {
+			added = append(added, x)
+		}
+	}
+	for _, x := range a {
+		if !inB[x] {
+			removed = append(removed, x)
+		}
+	}
+	return uniqueSorted(added), uniqueSorted(removed)
+}
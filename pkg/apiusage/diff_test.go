@@ -0,0 +1,79 @@
+package apiusage
+
+import (
+	"go/token"
+	"reflect"
+	"testing"
+)
+
+func TestDiffReports(t *testing.T) {
+	oldR := &Report{
+		Tests: []TestUsage{
+			{Name: "unchanged", APIGroups: []string{"a.openshift.io"}},
+			{Name: "changed", APIGroups: []string{"a.openshift.io", "b.openshift.io"}},
+			{Name: "removed", APIGroups: []string{"c.openshift.io"}},
+		},
+		Diagnostics: []Diagnostic{
+			{Position: token.Position{Filename: "t.go", Line: 10}, Message: "unsupported construct: *ast.StarExpr"},
+		},
+	}
+	newR := &Report{
+		Tests: []TestUsage{
+			{Name: "unchanged", APIGroups: []string{"a.openshift.io"}},
+			{Name: "changed", APIGroups: []string{"a.openshift.io", "d.openshift.io"}},
+			{Name: "added", APIGroups: []string{"e.openshift.io"}},
+			{Name: "added without usage", APIGroups: []string{}},
+		},
+		Diagnostics: []Diagnostic{
+			// shifted, but not new
+			{Position: token.Position{Filename: "t.go", Line: 12}, Message: "unsupported construct: *ast.StarExpr"},
+			{Position: token.Position{Filename: "t.go", Line: 20}, Message: "API Group could not be resolved"},
+		},
+	}
+
+	d := DiffReports(oldR, newR)
+
+	expectedTests := []TestDiff{
+		{Name: "added", Status: "added", AddedGroups: []string{"e.openshift.io"}},
+		{Name: "changed", Status: "changed", AddedGroups: []string{"d.openshift.io"}, RemovedGroups: []string{"b.openshift.io"}},
+		{Name: "removed", Status: "removed", RemovedGroups: []string{"c.openshift.io"}},
+	}
+	if !reflect.DeepEqual(d.Tests, expectedTests) {
+		t.Errorf("expected tests diff %+v, got %+v", expectedTests, d.Tests)
+	}
+	if !reflect.DeepEqual(d.AddedGroups, []string{"d.openshift.io", "e.openshift.io"}) {
+		t.Errorf("unexpected added groups: %v", d.AddedGroups)
+	}
+	if !reflect.DeepEqual(d.RemovedGroups, []string{"b.openshift.io", "c.openshift.io"}) {
+		t.Errorf("unexpected removed groups: %v", d.RemovedGroups)
+	}
+	if len(d.NewUnresolved) != 1 || d.NewUnresolved[0].Position.Line != 20 {
+		t.Errorf("expected single new unresolved call site at line 20, got %+v", d.NewUnresolved)
+	}
+}
+
+func TestDiffReportsDuplicateNames(t *testing.T) {
+	oldR := &Report{
+		Tests: []TestUsage{
+			{Name: "TestFoo", Package: "a", APIGroups: []string{"a.openshift.io"}},
+			{Name: "TestFoo", Package: "b", APIGroups: []string{"b.openshift.io"}},
+		},
+	}
+	newR := &Report{
+		Tests: []TestUsage{
+			{Name: "TestFoo", Package: "a", APIGroups: []string{"a.openshift.io"}},
+			{Name: "TestFoo", Package: "b", APIGroups: []string{"c.openshift.io"}},
+			{Name: "TestFoo", Package: "c", APIGroups: []string{"a.openshift.io"}},
+		},
+	}
+
+	d := DiffReports(oldR, newR)
+
+	expectedTests := []TestDiff{
+		{Name: "TestFoo", Package: "b", Status: "changed", AddedGroups: []string{"c.openshift.io"}, RemovedGroups: []string{"b.openshift.io"}},
+		{Name: "TestFoo", Package: "c", Status: "added", AddedGroups: []string{"a.openshift.io"}},
+	}
+	if !reflect.DeepEqual(d.Tests, expectedTests) {
+		t.Errorf("expected tests diff %+v, got %+v", expectedTests, d.Tests)
+	}
+}
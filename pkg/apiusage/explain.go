@@ -0,0 +1,60 @@
+package apiusage
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"strings"
+)
+
+const maxStepCodeLen = 120
+
+// ResolutionStep is a single piece of code visited when resolving API Group of a call site,
+// e.g. `gr := g2` when tracing `gr` back to its value.
+type ResolutionStep struct {
+	Position token.Position `json:"position"`
+	Code     string         `json:"code"`
+}
+
+// trace collects resolution steps. It's shared between investigators created for the same call site.
+type trace struct {
+	steps []ResolutionStep
+}
+
+// explain records node as a resolution step, if explanation was requested
+func (i *investigator) explain(n ast.Node) {
+	if i.trace == nil {
+		return
+	}
+	i.trace.steps = append(i.trace.steps, ResolutionStep{
+		Position: i.pkg.Fset.Position(n.Pos()),
+		Code:     nodeString(i.pkg.Fset, n),
+	})
+}
+
+// nodeString returns first line of the formatted node.
+// For function declarations and literals just the signature is returned.
+func nodeString(fset *token.FileSet, n ast.Node) string {
+	switch f := n.(type) {
+	case *ast.FuncDecl:
+		fdCopy := *f
+		fdCopy.Body = nil
+		n = &fdCopy
+	case *ast.FuncLit:
+		n = f.Type
+	}
+
+	buf := bytes.Buffer{}
+	if err := format.Node(&buf, fset, n); err != nil {
+		return "<unknown>"
+	}
+	s, _, multiline := strings.Cut(buf.String(), "\n")
+	if multiline {
+		s += " ..."
+	}
+	if len(s) > maxStepCodeLen {
+		s = s[:maxStepCodeLen] + " ..."
+	}
+	return s
+}
@@ -0,0 +1,104 @@
+package apiusage
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// deref returns type pointed to by a pointer, or the type itself
+func deref(t types.Type) types.Type {
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		return p.Elem()
+	}
+	return t
+}
+
+// fieldSelection returns selection of struct's field made by `x.F`, or nil if it's not a field
+// (e.g. qualified identifier or method) or the field is promoted from embedded struct
+func fieldSelection(info *types.Info, sel *ast.SelectorExpr) *types.Selection {
+	s, ok := info.Selections[sel]
+	if !ok || s.Kind() != types.FieldVal || len(s.Index()) != 1 {
+		return nil
+	}
+	return s
+}
+
+// fieldExpr returns API Groups of the selected field: `tc.gvr` or `gvr.Group`
+func (i *investigator) fieldExpr(sel *ast.SelectorExpr, s *types.Selection) []string {
+	if isTypeGVR(deref(s.Recv())) {
+		if s.Obj().Name() != "Group" {
+			i.unsupported(sel)
+		}
+		// gvr.Group
+		return i.analyzeExpr(sel.X)
+	}
+
+	groups := []string{}
+	i.fieldValues(s, func(i *investigator, value ast.Expr, result int) {
+		groups = append(groups, i.analyzeValue(value, result)...)
+	})
+	return groups
+}
+
+// fieldValues calls f for every value given to the field in the package declaring the struct and in the package of i:
+// in composite literals (`T{F: v}`) and by assignments (`x.F = v`). Values are collected regardless of
+// the struct value they're assigned to, e.g. all test cases of a table with GVR field are considered.
+func (i *investigator) fieldValues(s *types.Selection, f func(i *investigator, value ast.Expr, result int)) {
+	field := s.Obj()
+	if i.visiting[field] {
+		// x.gvrs = append(x.gvrs, ...)
+		return
+	}
+	i.visiting[field] = true
+	defer delete(i.visiting, field)
+
+	// structs are matched by type name, as the declaring package might have been loaded on demand
+	owner := types.TypeString(deref(s.Recv()), nil)
+	isOwner := func(info *types.Info, e ast.Expr) bool {
+		t := info.TypeOf(e)
+		return t != nil && types.TypeString(deref(t), nil) == owner
+	}
+
+	pkgs := []*packages.Package{i.pkg}
+	if field.Pkg() != nil && field.Pkg().Path() != i.pkg.PkgPath {
+		pkgs = append(pkgs, i.packageOf(field.Pkg().Path()))
+	}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			i2 := i.in(pkg, file)
+			ast.Inspect(file, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.CompositeLit:
+					if !isOwner(pkg.TypesInfo, n) {
+						return true
+					}
+					for idx, elt := range n.Elts {
+						if kv, ok := elt.(*ast.KeyValueExpr); ok {
+							// T{F: v}
+							if key, ok := kv.Key.(*ast.Ident); ok && key.Name == field.Name() {
+								i2.explain(kv)
+								f(i2, kv.Value, 0)
+							}
+						} else if idx == s.Index()[0] {
+							// T{v}
+							i2.explain(elt)
+							f(i2, elt, 0)
+						}
+					}
+				case *ast.AssignStmt:
+					for idx, lhs := range n.Lhs {
+						// x.F = v
+						if sel, ok := lhs.(*ast.SelectorExpr); ok && sel.Sel.Name == field.Name() && isOwner(pkg.TypesInfo, sel.X) {
+							i2.explain(n)
+							value, result := assignedValue(len(n.Lhs), n.Rhs, idx)
+							f(i2, value, result)
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package apiusage
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// testDataPath points to the module with fixtures: each test encodes expected API Groups in its name
+// using [apigroup:GROUP] tags.
+const testDataPath = "../../test_data"
+
+var apiGroupTagRx = regexp.MustCompile(`\[apigroup:([^\]]+)\]`)
+
+// mayTag marks fixtures whose call site is expected to be reported with CallSite.May,
+// because more than one definition of the GVR reaches it.
+const mayTag = "[may]"
+
+// knownFailures lists fixtures that the analyzer is not able to handle yet, with a reason.
+var knownFailures = map[string]string{
+	"ResourceInterface is created from DynamicConfig object, GVR is initialized literate gvr var is passed to a function [apigroup:33a9.openshift.io]": "GVR passed as function's argument is not traced back to the caller",
+	"Unsupported constructs GVR dereferenced from pointer is reported as unsupported construct [apigroup:5d1e.openshift.io]":                           "dereferenced pointers are not analyzed",
+}
+
+// kubernetesFixtures is a dir of fixtures using upstream Kubernetes API Groups, which are checked with Config.KubernetesGroups
+const kubernetesFixtures = "kubernetes_client_go"
+
+func expectedAPIGroups(testName string) []string {
+	groups := []string{}
+	for _, m := range apiGroupTagRx.FindAllStringSubmatch(testName, -1) {
+		if m[1] == coreGroupName {
+			m[1] = ""
+		}
+		groups = append(groups, m[1])
+	}
+	return uniqueSorted(groups)
+}
+
+func TestFixtures(t *testing.T) {
+	testFixtures(t, Config{
+		RepoPath:     testDataPath,
+		IncludeRoots: []string{"test"},
+		Excludes:     append([]string{kubernetesFixtures}, DefaultExcludes...),
+		CallGraph:    CallGraphCHA,
+	})
+}
+
+func TestKubernetesFixtures(t *testing.T) {
+	testFixtures(t, Config{
+		RepoPath:         testDataPath,
+		IncludeRoots:     []string{"test/extended/" + kubernetesFixtures},
+		CallGraph:        CallGraphCHA,
+		KubernetesGroups: true,
+	})
+}
+
+func testFixtures(t *testing.T, cfg Config) {
+	report, err := Analyze(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(report.Tests) == 0 {
+		t.Fatalf("no tests found in %s", testDataPath)
+	}
+
+	// go test's test runs its subtests, so it's expected to use their API Groups too
+	subtestGroups := map[string][]string{}
+	for _, tu := range report.Tests {
+		if tu.Framework != FrameworkGoTest {
+			continue
+		}
+		for idx := strings.Index(tu.Name, "/"); idx != -1; idx = nextIndex(tu.Name, "/", idx) {
+			parent := tu.Package + "." + tu.Name[:idx]
+			subtestGroups[parent] = append(subtestGroups[parent], expectedAPIGroups(tu.Name)...)
+		}
+	}
+
+	for _, tu := range report.Tests {
+		tu := tu
+		t.Run(tu.Name, func(t *testing.T) {
+			expected := uniqueSorted(append(expectedAPIGroups(tu.Name), subtestGroups[tu.Package+"."+tu.Name]...))
+			// groups outside of openshift.io (like in "gvr outside openshift.io should be ignored" fixture)
+			// are dropped by DefaultGroups
+			actual := tu.APIGroups
+			if len(expected) == 0 && len(tu.CallSites) == 0 {
+				// e.g. parent of t.Run subtests
+				return
+			}
+
+			may := false
+			for _, cs := range tu.CallSites {
+				may = may || cs.May
+			}
+			if strings.Contains(tu.Name, mayTag) != may {
+				t.Errorf("%s: expected call site marked as may: %v, got %v", tu.Position, !may, may)
+			}
+
+			reason, isKnownFailure := knownFailures[tu.Name]
+			if reflect.DeepEqual(expected, actual) {
+				if isKnownFailure {
+					t.Errorf("fixture passes but is listed as known failure (%s) - remove it from knownFailures", reason)
+				}
+				return
+			}
+			if isKnownFailure {
+				t.Skipf("known failure: %s", reason)
+			}
+			t.Errorf("%s: expected API Groups %v, got %v", tu.Position, expected, actual)
+		})
+	}
+}
+
+// nextIndex returns index of sep in s after index idx, or -1
+func nextIndex(s, sep string, idx int) int {
+	if next := strings.Index(s[idx+1:], sep); next != -1 {
+		return idx + 1 + next
+	}
+	return -1
+}
+
+func TestUnsupportedConstructDiagnostic(t *testing.T) {
+	report, err := Analyze(context.Background(), Config{RepoPath: testDataPath, IncludeRoots: []string{"test/extended/dynamic_client_go"}})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	for _, d := range report.Diagnostics {
+		if d.Message != "unsupported construct: *ast.StarExpr" {
+			continue
+		}
+		if d.Construct == nil || d.Construct.Filename != "test/extended/dynamic_client_go/unsupported.go" || d.Construct.Line != 15 {
+			t.Errorf("expected construct at unsupported.go:15, got %v", d.Construct)
+		}
+		return
+	}
+	t.Errorf("diagnostic of unsupported construct not found in %+v", report.Diagnostics)
+}
@@ -0,0 +1,46 @@
+package apiusage
+
+import (
+	"golang.org/x/tools/go/packages"
+)
+
+// Framework is a test framework used to define tests.
+type Framework string
+
+const (
+	FrameworkGinkgoV1 Framework = "ginkgo"
+	FrameworkGinkgoV2 Framework = "ginkgo/v2"
+	FrameworkGoTest   Framework = "testing"
+)
+
+var frameworkImportPaths = map[string]Framework{
+	"github.com/onsi/ginkgo":    FrameworkGinkgoV1,
+	"github.com/onsi/ginkgo/v2": FrameworkGinkgoV2,
+	"testing":                   FrameworkGoTest,
+}
+
+// detectFrameworks returns test frameworks imported by the package.
+// If enabled is not empty, only frameworks from that list are returned.
+func detectFrameworks(pkg *packages.Package, enabled []Framework) []Framework {
+	frameworks := []Framework{}
+	for path := range pkg.Imports {
+		fw, ok := frameworkImportPaths[path]
+		if !ok {
+			continue
+		}
+		if len(enabled) != 0 && getIndex(enabled, func(e Framework) bool { return e == fw }) == -1 {
+			continue
+		}
+		frameworks = append(frameworks, fw)
+	}
+	return frameworks
+}
+
+func hasFramework(frameworks []Framework, fws ...Framework) bool {
+	for _, fw := range fws {
+		if getIndex(frameworks, func(f Framework) bool { return f == fw }) != -1 {
+			return true
+		}
+	}
+	return false
+}
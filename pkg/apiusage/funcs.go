@@ -0,0 +1,83 @@
+package apiusage
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// funcParts returns signature and body of *ast.FuncDecl or *ast.FuncLit
+func (i *investigator) funcParts(fun ast.Node) (*ast.FuncType, *ast.BlockStmt) {
+	switch fun := fun.(type) {
+	case *ast.FuncDecl:
+		return fun.Type, fun.Body
+	case *ast.FuncLit:
+		return fun.Type, fun.Body
+	default:
+		i.unsupported(fun)
+	}
+	return nil, nil
+}
+
+// funcVarResult returns API Groups of GVRs returned as result with given index by functions stored in the variable:
+// all function literals or functions assigned to local variable and reaching the call, or the value of package-level variable
+func (i *investigator) funcVarResult(id *ast.Ident, v *types.Var, result int) []string {
+	i2, decl, idx := i.declarationOf(id)
+	if decl == nil {
+		i.unsupported(id)
+	}
+	defs := []definition{{node: decl, index: idx}}
+	if i2 == i && v.Parent() != v.Pkg().Scope() {
+		defs = i.reachingDefinitions(id, v)
+		if len(defs) > 1 {
+			i.markMay()
+		}
+	}
+
+	groups := []string{}
+	for _, d := range defs {
+		i2.explain(d.node)
+		var value ast.Expr
+		switch node := d.node.(type) {
+		case *ast.AssignStmt:
+			assert(len(node.Lhs) == len(node.Rhs))
+			value = node.Rhs[d.index]
+		case *ast.ValueSpec:
+			assert(len(node.Names) == len(node.Values))
+			value = node.Values[d.index]
+		default:
+			// function passed as parameter
+			i2.unsupported(d.node)
+		}
+		groups = append(groups, i2.funcValueResult(value, result)...)
+	}
+	return groups
+}
+
+// funcValueResult returns API Groups of GVRs returned as result with given index by function given by the expression:
+// function literal, function or another variable holding function
+func (i *investigator) funcValueResult(value ast.Expr, result int) []string {
+	var id *ast.Ident
+	switch value := ast.Unparen(value).(type) {
+	case *ast.FuncLit:
+		// mk := func() GVR { ... }
+		return i.analyzeFunction(value, result)
+	case *ast.Ident:
+		// mk := helper
+		id = value
+	case *ast.SelectorExpr:
+		// mk := pkg.Helper
+		id = value.Sel
+	default:
+		i.unsupported(value)
+	}
+
+	switch obj := i.pkg.TypesInfo.Uses[id].(type) {
+	case *types.Func:
+		return i.callFunction(value, id, obj, result)
+	case *types.Var:
+		return i.funcVarResult(id, obj, result)
+	default:
+		i.unsupported(id)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package apiusage
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/packages"
+)
+
+const ginkgoPkgPrefix = "github.com/onsi/ginkgo"
+
+var (
+	ginkgoContainerFuncs = map[string]bool{
+		"Describe": true, "FDescribe": true, "PDescribe": true, "XDescribe": true,
+		"Context": true, "FContext": true, "PContext": true, "XContext": true,
+		"When": true, "FWhen": true, "PWhen": true, "XWhen": true,
+	}
+	ginkgoSpecFuncs = map[string]bool{
+		"It": true, "FIt": true, "PIt": true, "XIt": true,
+		"Specify": true, "FSpecify": true, "PSpecify": true, "XSpecify": true,
+	}
+)
+
+// spec is a single test: Ginkgo's g.It together with its enclosing containers (g.Describe, g.Context, ...),
+// or go test's TestXxx function or t.Run() subtest
+type spec struct {
+	name       string
+	framework  Framework
+	node       ast.Node
+	containers []ast.Node
+}
+
+// ginkgoFramework returns Ginkgo version providing object obj, or empty string if obj is not from Ginkgo.
+func ginkgoFramework(obj types.Object) Framework {
+	if obj == nil || obj.Pkg() == nil || !strings.HasPrefix(obj.Pkg().Path(), ginkgoPkgPrefix) {
+		return ""
+	}
+	if strings.HasPrefix(obj.Pkg().Path(), ginkgoPkgPrefix+"/v2") {
+		return FrameworkGinkgoV2
+	}
+	return FrameworkGinkgoV1
+}
+
+// ginkgoFuncName returns name of the Ginkgo function called by ce, or empty string if ce is not a call to Ginkgo.
+// Both `g.It()` and dot-imported `It()` are recognized.
+func ginkgoFuncName(info *types.Info, ce *ast.CallExpr) string {
+	id := calleeIdent(ce)
+	if id == nil {
+		return ""
+	}
+	obj := info.Uses[id]
+	if ginkgoFramework(obj) == "" {
+		return ""
+	}
+	return obj.Name()
+}
+
+// calleeIdent returns identifier of called function for `pkg.F()` and `F()` calls,
+// including explicitly instantiated generic functions: `F[T]()`.
+func calleeIdent(ce *ast.CallExpr) *ast.Ident {
+	fun := ast.Unparen(ce.Fun)
+	switch index := fun.(type) {
+	case *ast.IndexExpr:
+		fun = index.X
+	case *ast.IndexListExpr:
+		fun = index.X
+	}
+	switch fun := fun.(type) {
+	case *ast.SelectorExpr:
+		return fun.Sel
+	case *ast.Ident:
+		return fun
+	}
+	return nil
+}
+
+func isGinkgoContainer(info *types.Info, ce *ast.CallExpr) bool {
+	return ginkgoContainerFuncs[ginkgoFuncName(info, ce)]
+}
+
+func isGinkgoSpec(info *types.Info, ce *ast.CallExpr) bool {
+	return ginkgoSpecFuncs[ginkgoFuncName(info, ce)]
+}
+
+// ginkgoText returns description text passed as first argument to Ginkgo's container or spec.
+func ginkgoText(info *types.Info, ce *ast.CallExpr) string {
+	if len(ce.Args) == 0 {
+		return ""
+	}
+	if bl, ok := ce.Args[0].(*ast.BasicLit); ok {
+		if s, err := strconv.Unquote(bl.Value); err == nil {
+			return s
+		}
+	}
+	if tv, ok := info.Types[ce.Args[0]]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+		return constant.StringVal(tv.Value)
+	}
+	return "<unknown>"
+}
+
+// findSpecs returns all Ginkgo specs defined in the package.
+func findSpecs(pkg *packages.Package) []*spec {
+	specs := []*spec{}
+
+	i := inspector.New(pkg.Syntax)
+	i.WithStack(
+		[]ast.Node{&ast.CallExpr{}},
+		func(n ast.Node, push bool, stack []ast.Node) (proceed bool) {
+			proceed = true
+			if !push {
+				return
+			}
+
+			callExpr := n.(*ast.CallExpr)
+			if !isGinkgoSpec(pkg.TypesInfo, callExpr) {
+				return
+			}
+
+			s := &spec{node: callExpr, framework: ginkgoFramework(pkg.TypesInfo.Uses[calleeIdent(callExpr)])}
+			texts := []string{}
+			for _, n := range stack {
+				if ce, ok := n.(*ast.CallExpr); ok && isGinkgoContainer(pkg.TypesInfo, ce) {
+					s.containers = append(s.containers, ce)
+					texts = append(texts, ginkgoText(pkg.TypesInfo, ce))
+				}
+			}
+			s.name = strings.Join(append(texts, ginkgoText(pkg.TypesInfo, callExpr)), " ")
+			specs = append(specs, s)
+			return
+		},
+	)
+
+	return specs
+}
+
+// specsForNode returns specs that execute node which is located on top of the stack:
+// if the node is inside a spec, that spec is returned together with enclosing specs (parents of go test's subtests,
+// which run their subtests), if the node is inside a container (but not spec), all specs of that container are returned.
+func specsForNode(specs []*spec, stack []ast.Node) []*spec {
+	found := []*spec{}
+	for idx := len(stack) - 1; idx >= 0; idx-- {
+		n := stack[idx]
+		found = append(found, filter(specs, func(s *spec) bool { return s.node == n })...)
+		if len(found) != 0 {
+			continue
+		}
+		inContainer := filter(specs, func(s *spec) bool {
+			return getIndex(s.containers, func(c ast.Node) bool { return c == n }) != -1
+		})
+		if len(inContainer) != 0 {
+			return inContainer
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+	return found
+}
@@ -0,0 +1,225 @@
+package apiusage
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// findGoTests returns all `func TestXxx(t *testing.T)` tests and their `t.Run()` subtests defined in the package.
+func findGoTests(pkg *packages.Package, decls *declarations) []*spec {
+	specs := []*spec{}
+	for _, file := range pkg.Syntax {
+		if !strings.HasSuffix(pkg.Fset.Position(file.Pos()).Filename, "_test.go") {
+			continue
+		}
+		for _, decl := range file.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || !isGoTestFunc(pkg.TypesInfo, fd) {
+				continue
+			}
+			specs = append(specs, &spec{name: fd.Name.Name, framework: FrameworkGoTest, node: fd})
+			specs = append(specs, findSubtests(pkg, decls, fd.Body, []string{fd.Name.Name})...)
+		}
+	}
+	return specs
+}
+
+// isGoTestFunc checks if function is a test recognized by `go test`: func TestXxx(t *testing.T)
+func isGoTestFunc(info *types.Info, fd *ast.FuncDecl) bool {
+	if fd.Recv != nil || fd.Body == nil || !strings.HasPrefix(fd.Name.Name, "Test") {
+		return false
+	}
+	if suffix := fd.Name.Name[len("Test"):]; suffix != "" {
+		if r, _ := utf8.DecodeRuneInString(suffix); unicode.IsLower(r) {
+			return false
+		}
+	}
+
+	obj, ok := info.Defs[fd.Name]
+	if !ok || obj == nil {
+		return false
+	}
+	signature := obj.Type().(*types.Signature)
+	return signature.Params().Len() == 1 &&
+		signature.Params().At(0).Type().String() == "*testing.T" &&
+		signature.Results().Len() == 0
+}
+
+// isTRun checks if ce is a `t.Run(name, func(t *testing.T) {...})` call
+func isTRun(info *types.Info, ce *ast.CallExpr) bool {
+	sel, ok := ce.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Run" || len(ce.Args) != 2 {
+		return false
+	}
+	typ, ok := info.Types[sel.X]
+	return ok && typ.Type.String() == "*testing.T"
+}
+
+// findSubtests looks for t.Run() calls in the body and returns subtests (including nested ones) with full names,
+// i.e. prefixed with parents' names. If name of a subtest can have multiple values (e.g. t.Run is inside a loop over
+// test cases), a spec for each name is returned.
+func findSubtests(pkg *packages.Package, decls *declarations, body ast.Node, parents []string) []*spec {
+	specs := []*spec{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		ce, ok := n.(*ast.CallExpr)
+		if !ok || !isTRun(pkg.TypesInfo, ce) {
+			return true
+		}
+
+		names := []string{}
+		for _, parent := range parents {
+			for _, sub := range subtestNames(pkg, decls, ce.Args[0]) {
+				names = append(names, parent+"/"+sub)
+			}
+		}
+		for _, name := range names {
+			specs = append(specs, &spec{name: name, framework: FrameworkGoTest, node: ce})
+		}
+		specs = append(specs, findSubtests(pkg, decls, ce.Args[1], names)...)
+
+		// nested t.Run() calls were already handled
+		return false
+	})
+	return specs
+}
+
+// subtestNames tries to statically evaluate name passed to t.Run().
+// Following cases are supported:
+// - constant: t.Run("name", ...)
+// - field of test case when looping over a literal: for _, tc := range []struct{name string}{...} { t.Run(tc.name, ...) }
+// - key when looping over a literal map: for name, tc := range map[string]struct{}{...} { t.Run(name, ...) }
+func subtestNames(pkg *packages.Package, decls *declarations, e ast.Expr) []string {
+	info := pkg.TypesInfo
+	if tv, ok := info.Types[e]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+		return []string{rewriteSubtestName(constant.StringVal(tv.Value))}
+	}
+
+	unknown := []string{"<unknown>"}
+	var id *ast.Ident
+	field := ""
+	switch e := e.(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		x, ok := e.X.(*ast.Ident)
+		if !ok {
+			return unknown
+		}
+		id = x
+		field = e.Sel.Name
+	default:
+		return unknown
+	}
+	// for KEY, VALUE := range X
+	as, ok := localDeclarationOf(pkg, decls, id).(*ast.AssignStmt)
+	if !ok || len(as.Rhs) != 1 {
+		return unknown
+	}
+	rng, ok := as.Rhs[0].(*ast.UnaryExpr)
+	if !ok || rng.Op != token.RANGE {
+		return unknown
+	}
+	key, _ := as.Lhs[0].(*ast.Ident)
+	isKey := key != nil && info.ObjectOf(key) == info.ObjectOf(id)
+	cl := rangedCompositeLit(pkg, decls, rng.X)
+	if cl == nil {
+		return unknown
+	}
+
+	names := []string{}
+	for _, elt := range cl.Elts {
+		var v ast.Expr = elt
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if isKey {
+				v = kv.Key
+			} else {
+				v = kv.Value
+			}
+		} else if isKey {
+			// key of slice is an index
+			return unknown
+		}
+
+		if field != "" {
+			v = structLitField(v, field)
+			if v == nil {
+				return unknown
+			}
+		}
+
+		tv, ok := info.Types[v]
+		if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+			return unknown
+		}
+		names = append(names, rewriteSubtestName(constant.StringVal(tv.Value)))
+	}
+	return names
+}
+
+// rangedCompositeLit returns composite literal being ranged over: either directly or via variable
+func rangedCompositeLit(pkg *packages.Package, decls *declarations, e ast.Expr) *ast.CompositeLit {
+	switch e := e.(type) {
+	case *ast.CompositeLit:
+		return e
+	case *ast.Ident:
+		switch decl := localDeclarationOf(pkg, decls, e).(type) {
+		case *ast.AssignStmt:
+			if len(decl.Rhs) == 1 {
+				cl, _ := decl.Rhs[0].(*ast.CompositeLit)
+				return cl
+			}
+		case *ast.ValueSpec:
+			if len(decl.Values) == 1 {
+				cl, _ := decl.Values[0].(*ast.CompositeLit)
+				return cl
+			}
+		}
+	}
+	return nil
+}
+
+// structLitField returns value of the field of a struct literal, e.g. for `{name: "x"}` and "name" returns `"x"`
+func structLitField(e ast.Expr, field string) ast.Expr {
+	if ue, ok := e.(*ast.UnaryExpr); ok && ue.Op == token.AND {
+		// &tc{...}
+		e = ue.X
+	}
+	cl, ok := e.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	for _, elt := range cl.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok && key.Name == field {
+				return kv.Value
+			}
+		}
+	}
+	return nil
+}
+
+// rewriteSubtestName mimics how `testing` package rewrites subtest names: spaces are replaced with underscores
+// and non-printable characters are escaped.
+func rewriteSubtestName(s string) string {
+	b := strings.Builder{}
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r):
+			b.WriteRune('_')
+		case !strconv.IsPrint(r):
+			s := strconv.QuoteRune(r)
+			b.WriteString(s[1 : len(s)-1])
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
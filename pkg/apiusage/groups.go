@@ -0,0 +1,106 @@
+package apiusage
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// DefaultGroups are used if Config.Groups is empty: only OpenShift API Groups are reported.
+var DefaultGroups = []string{"*.openshift.io"}
+
+// coreGroupName is used in place of the core API Group (which is empty string) in Config.Groups patterns
+const coreGroupName = "core"
+
+// GroupClass tells where API Group comes from.
+type GroupClass string
+
+const (
+	// GroupClassOpenShift is API Group of OpenShift (*.openshift.io).
+	GroupClassOpenShift GroupClass = "openshift"
+	// GroupClassKubernetes is API Group of upstream Kubernetes (*.k8s.io and groups like apps or batch).
+	GroupClassKubernetes GroupClass = "kubernetes"
+	// GroupClassCore is the core (legacy) API Group, i.e. empty group of Pods, Services, ConfigMaps, etc.
+	GroupClassCore GroupClass = "core"
+	// GroupClassThirdParty is any other API Group, e.g. of a CRD installed by an operator.
+	GroupClassThirdParty GroupClass = "third-party"
+)
+
+// kubernetesGroups are upstream Kubernetes API Groups without .k8s.io suffix
+var kubernetesGroups = map[string]bool{
+	"apps":        true,
+	"autoscaling": true,
+	"batch":       true,
+	"extensions":  true,
+	"policy":      true,
+}
+
+// ClassifyGroup returns class of the API Group.
+func ClassifyGroup(group string) GroupClass {
+	switch {
+	case group == "":
+		return GroupClassCore
+	case group == "openshift.io" || strings.HasSuffix(group, ".openshift.io"):
+		return GroupClassOpenShift
+	case group == "k8s.io" || strings.HasSuffix(group, ".k8s.io") || kubernetesGroups[group]:
+		return GroupClassKubernetes
+	default:
+		return GroupClassThirdParty
+	}
+}
+
+// classifyGroups returns classes of all the groups
+func classifyGroups(groups []string) map[string]GroupClass {
+	classes := map[string]GroupClass{}
+	for _, g := range groups {
+		classes[g] = ClassifyGroup(g)
+	}
+	return classes
+}
+
+// groupFilter selects API Groups that are reported.
+type groupFilter struct {
+	include []string
+	exclude []string
+	// kubernetes keeps upstream Kubernetes groups even if they don't match any including pattern
+	kubernetes bool
+}
+
+// newGroupFilter parses glob patterns (see path.Match) of API Groups. Patterns prefixed with "!" exclude groups.
+// Group is kept if it matches any of including patterns (or there are none) and none of excluding ones.
+// Core API Group is matched as "core".
+func newGroupFilter(patterns []string) (*groupFilter, error) {
+	f := &groupFilter{}
+	for _, p := range patterns {
+		neg := strings.HasPrefix(p, "!")
+		p = strings.TrimPrefix(p, "!")
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid API Group pattern %q: %w", p, err)
+		}
+		if neg {
+			f.exclude = append(f.exclude, p)
+		} else {
+			f.include = append(f.include, p)
+		}
+	}
+	return f, nil
+}
+
+func (f *groupFilter) matches(group string) bool {
+	class := ClassifyGroup(group)
+	upstream := f.kubernetes && (class == GroupClassCore || class == GroupClassKubernetes)
+	if group == "" {
+		group = coreGroupName
+	}
+	match := func(patterns []string) bool {
+		return getIndex(patterns, func(p string) bool {
+			m, _ := path.Match(p, group)
+			return m
+		}) != -1
+	}
+	return (len(f.include) == 0 || match(f.include) || upstream) && !match(f.exclude)
+}
+
+func (f *groupFilter) filter(groups []string) []string {
+	return filter(groups, f.matches)
+}
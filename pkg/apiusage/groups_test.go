@@ -0,0 +1,54 @@
+package apiusage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupFilter(t *testing.T) {
+	groups := []string{"", "apps", "config.openshift.io", "operator.openshift.io", "storage.k8s.io", "example.com"}
+	tests := []struct {
+		patterns []string
+		expected []string
+	}{
+		{patterns: DefaultGroups, expected: []string{"config.openshift.io", "operator.openshift.io"}},
+		{patterns: []string{"*.openshift.io", "!operator.openshift.io"}, expected: []string{"config.openshift.io"}},
+		{patterns: []string{"!*.openshift.io"}, expected: []string{"", "apps", "storage.k8s.io", "example.com"}},
+		{patterns: []string{"core", "*.k8s.io"}, expected: []string{"", "storage.k8s.io"}},
+		{patterns: []string{"*"}, expected: groups},
+	}
+	for _, tt := range tests {
+		f, err := newGroupFilter(tt.patterns)
+		if err != nil {
+			t.Fatalf("%v: %v", tt.patterns, err)
+		}
+		if actual := f.filter(groups); !reflect.DeepEqual(tt.expected, actual) {
+			t.Errorf("%v: expected %v, got %v", tt.patterns, tt.expected, actual)
+		}
+	}
+
+	f, _ := newGroupFilter([]string{"*.openshift.io", "!apps"})
+	f.kubernetes = true
+	if actual, expected := f.filter(groups), []string{"", "config.openshift.io", "operator.openshift.io", "storage.k8s.io"}; !reflect.DeepEqual(expected, actual) {
+		t.Errorf("kubernetes groups: expected %v, got %v", expected, actual)
+	}
+
+	if _, err := newGroupFilter([]string{"[invalid"}); err == nil {
+		t.Errorf("expected error for invalid pattern")
+	}
+}
+
+func TestClassifyGroup(t *testing.T) {
+	expected := map[string]GroupClass{
+		"":                          GroupClassCore,
+		"route.openshift.io":        GroupClassOpenShift,
+		"apps":                      GroupClassKubernetes,
+		"rbac.authorization.k8s.io": GroupClassKubernetes,
+		"monitoring.coreos.com":     GroupClassThirdParty,
+	}
+	for g, c := range expected {
+		if actual := ClassifyGroup(g); actual != c {
+			t.Errorf("%q: expected %s, got %s", g, c, actual)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package apiusage
+
+import "sync"
+
+func getValues[K comparable, V comparable](m map[K]V) []V {
+	s := make([]V, 0, len(m))
+	for _, v := range m {
+		s = append(s, v)
+	}
+	return s
+}
+
+func filter[T comparable](xs []T, pred func(T) bool) []T {
+	xs2 := []T{}
+	for _, x := range xs {
+		if pred(x) {
+			xs2 = append(xs2, x)
+		}
+	}
+	return xs2
+}
+
+func getIndex[T comparable](xs []T, pred func(T) bool) int {
+	for idx, x := range xs {
+		if pred(x) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// parallelize calls f for each index in [0, n) using at most workers goroutines
+func parallelize(workers, n int, f func(idx int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	idxs := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range idxs {
+				f(idx)
+			}
+		}()
+	}
+	for idx := 0; idx < n; idx++ {
+		idxs <- idx
+	}
+	close(idxs)
+	wg.Wait()
+}
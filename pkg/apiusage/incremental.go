@@ -0,0 +1,181 @@
+package apiusage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// changedFiles returns absolute paths of files changed since the git ref, including uncommitted and untracked files.
+func changedFiles(ctx context.Context, repoPath, since string) ([]string, error) {
+	git := func(args ...string) ([]string, error) {
+		cmd := exec.CommandContext(ctx, "git", append([]string{"-C", repoPath}, args...)...)
+		stderr := bytes.Buffer{}
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("git %s failed: %w: %s", strings.Join(args, " "), err, stderr.String())
+		}
+		lines := []string{}
+		for _, l := range strings.Split(string(out), "\n") {
+			if l = strings.TrimSpace(l); l != "" {
+				lines = append(lines, l)
+			}
+		}
+		return lines, nil
+	}
+
+	top, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	if len(top) != 1 {
+		return nil, fmt.Errorf("unexpected output of git rev-parse: %v", top)
+	}
+	// renames are listed as deleted and added files, so dirs of both are affected
+	diff, err := git("diff", "--name-only", "--no-renames", since)
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := git("ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, f := range append(diff, untracked...) {
+		files = append(files, filepath.Join(top[0], f))
+	}
+	return files, nil
+}
+
+// isModuleFile checks if file affects whole module, e.g. changes versions of dependencies
+func isModuleFile(path string) bool {
+	switch filepath.Base(path) {
+	case "go.mod", "go.sum", "go.work", "go.work.sum", "modules.txt":
+		return true
+	}
+	return false
+}
+
+// affectedUnits limits load units to packages affected by changed files: packages in dirs of changed (or deleted) Go files
+// and packages that (transitively) import them. Only package names, files and imports are loaded for that, which is
+// much cheaper than full type checking.
+// If any of the module files changed, units are returned untouched.
+func affectedUnits(ctx context.Context, units []loadUnit, changedFiles []string, tests bool) ([]loadUnit, error) {
+	changedDirs := map[string]bool{}
+	for _, f := range changedFiles {
+		if isModuleFile(f) {
+			return units, nil
+		}
+		if strings.HasSuffix(f, ".go") {
+			changedDirs[filepath.Dir(f)] = true
+		}
+	}
+
+	res := []loadUnit{}
+	for _, u := range units {
+		cfg := packages.Config{
+			Mode:    packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps,
+			Context: ctx,
+			Dir:     u.dir,
+			Tests:   tests,
+		}
+		roots, err := packages.Load(&cfg, u.patterns...)
+		if err != nil {
+			return nil, fmt.Errorf("packages.Load failed: %w", err)
+		}
+
+		// affected[pkg] is true if pkg or any of its dependencies contains a changed file
+		affected := map[*packages.Package]bool{}
+		var isAffected func(p *packages.Package) bool
+		isAffected = func(p *packages.Package) bool {
+			if a, ok := affected[p]; ok {
+				return a
+			}
+			// assume not affected while visiting to handle import cycles
+			affected[p] = false
+			// deleted files aren't listed in GoFiles anymore, so package is matched by its dir
+			a := getIndex(p.GoFiles, func(f string) bool { return changedDirs[filepath.Dir(f)] }) != -1
+			for _, imp := range p.Imports {
+				if isAffected(imp) {
+					a = true
+				}
+			}
+			affected[p] = a
+			return a
+		}
+
+		dirs := map[string]bool{}
+		patterns := []string{}
+		for _, root := range roots {
+			if len(root.GoFiles) == 0 || !isAffected(root) {
+				continue
+			}
+			dir := filepath.Dir(root.GoFiles[0])
+			if !dirs[dir] {
+				dirs[dir] = true
+				patterns = append(patterns, dir)
+			}
+		}
+		if len(patterns) != 0 {
+			res = append(res, loadUnit{dir: u.dir, patterns: patterns})
+		}
+	}
+	return res, nil
+}
+
+// affectedDirs returns dirs, relative to repoPath, of analyzed packages and changed files
+func affectedDirs(repoPath string, pkgs []*packages.Package, changedFiles []string) map[string]bool {
+	dirs := map[string]bool{}
+	add := func(file string) {
+		if rel, err := filepath.Rel(repoPath, filepath.Dir(file)); err == nil {
+			dirs[rel] = true
+		}
+	}
+	for _, p := range pkgs {
+		for _, f := range p.GoFiles {
+			add(f)
+		}
+	}
+	for _, f := range changedFiles {
+		add(f)
+	}
+	return dirs
+}
+
+// mergeReports combines previous report with report of incremental analysis:
+// everything located in affected dirs is taken from the current report, the rest from the previous one.
+func mergeReports(previous, current *Report, affected map[string]bool) *Report {
+	isAffected := func(filename string) bool {
+		return affected[filepath.Dir(filename)]
+	}
+
+	res := &Report{
+		Tests:        append([]TestUsage{}, current.Tests...),
+		Unattributed: append([]CallSite{}, current.Unattributed...),
+		Diagnostics:  append([]Diagnostic{}, current.Diagnostics...),
+	}
+	for _, t := range previous.Tests {
+		if !isAffected(t.Position.Filename) {
+			res.Tests = append(res.Tests, t)
+		}
+	}
+	for _, cs := range previous.Unattributed {
+		if !isAffected(cs.Position.Filename) {
+			res.Unattributed = append(res.Unattributed, cs)
+		}
+	}
+	for _, d := range previous.Diagnostics {
+		if !isAffected(d.Position.Filename) {
+			res.Diagnostics = append(res.Diagnostics, d)
+		}
+	}
+	res.sort()
+	return res
+}
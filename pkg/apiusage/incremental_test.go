@@ -0,0 +1,87 @@
+package apiusage
+
+import (
+	"context"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestAffectedUnits(t *testing.T) {
+	repoPath, err := filepath.Abs(testDataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	units, err := getLoadUnits(Config{RepoPath: repoPath, IncludeRoots: []string{"test/extended"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := func(d string) string { return filepath.Join(repoPath, "test/extended", d) }
+
+	expected := map[string][]string{
+		// importers of changed package are affected too
+		"changed": {dir("dynamic_client_go"), dir("dynamic_client_go/other_pkg")},
+		"deleted": {dir("rest_paths")},
+		// with --no-renames, renamed file is listed under both old and new name
+		"renamed": {dir("cli"), dir("rest_paths")},
+	}
+	changedFiles := map[string][]string{
+		"changed": {filepath.Join(dir("dynamic_client_go/other_pkg"), "other.go")},
+		"deleted": {filepath.Join(dir("rest_paths"), "deleted.go")},
+		"renamed": {filepath.Join(dir("cli"), "old.go"), filepath.Join(dir("rest_paths"), "rest.go")},
+	}
+	for name, files := range changedFiles {
+		affected, err := affectedUnits(context.Background(), units, files, true)
+		if err != nil {
+			t.Fatalf("%s: affectedUnits failed: %v", name, err)
+		}
+		actual := []string{}
+		for _, u := range affected {
+			actual = append(actual, u.patterns...)
+		}
+		sort.Strings(actual)
+		if !reflect.DeepEqual(actual, expected[name]) {
+			t.Errorf("%s: expected %v, got %v", name, expected[name], actual)
+		}
+	}
+
+	affected, err := affectedUnits(context.Background(), units, []string{filepath.Join(repoPath, "go.mod")}, true)
+	if err != nil || !reflect.DeepEqual(affected, units) {
+		t.Errorf("expected all units to be affected by go.mod change, got %v (%v)", affected, err)
+	}
+}
+
+func TestMergeReports(t *testing.T) {
+	test := func(file string) TestUsage {
+		return TestUsage{Name: file, Position: token.Position{Filename: file, Line: 1}}
+	}
+	previous := &Report{Tests: []TestUsage{test("a/a.go"), test("b/b.go"), test("c/old.go"), test("e/e.go")}}
+
+	expected := map[string][]string{
+		"changed": {"a/a.go", "b/b.go", "c/old.go", "e/e.go"},
+		"deleted": {"a/a.go", "c/old.go", "e/e.go"},
+		"renamed": {"a/a.go", "b/b.go", "d/new.go", "e/e.go"},
+	}
+	changes := map[string]struct {
+		changedFiles []string
+		current      *Report
+	}{
+		"changed": {[]string{"/repo/a/a.go"}, &Report{Tests: []TestUsage{test("a/a.go")}}},
+		// package b is gone, so are its tests
+		"deleted": {[]string{"/repo/b/b.go"}, &Report{}},
+		"renamed": {[]string{"/repo/c/old.go", "/repo/d/new.go"}, &Report{Tests: []TestUsage{test("d/new.go")}}},
+	}
+	for name, c := range changes {
+		merged := mergeReports(previous, c.current, affectedDirs("/repo", nil, c.changedFiles))
+		actual := []string{}
+		for _, t := range merged.Tests {
+			actual = append(actual, t.Position.Filename)
+		}
+		sort.Strings(actual)
+		if !reflect.DeepEqual(actual, expected[name]) {
+			t.Errorf("%s: expected %v, got %v", name, expected[name], actual)
+		}
+	}
+}
@@ -0,0 +1,459 @@
+package apiusage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// loadUnit is a set of package patterns loaded together from a dir
+type loadUnit struct {
+	dir      string
+	patterns []string
+}
+
+// loadPackages loads packages selected by the cfg.
+// Directories are grouped by the Go module they belong to, and each module is loaded separately,
+// so repositories consisting of multiple modules (with or without go.work) are supported.
+// If changedFiles is not nil, only packages affected by the changes are loaded (see affectedUnits).
+//
+// Only selected packages are parsed and type-checked from source, dependencies are type-checked from export data
+// (compiled by go list, so they benefit from the build cache). Syntax of dependencies is loaded on demand,
+// when resolving API Groups requires it (see packageLoader). The exception is call graph: it can only traverse
+// functions with syntax, so all dependencies belonging to the analyzed modules are loaded from source as well.
+func loadPackages(ctx context.Context, cfg Config, changedFiles []string) ([]*packages.Package, error) {
+	// _test.go files are only needed for go test's tests
+	tests := len(cfg.Frameworks) == 0 || hasFramework(cfg.Frameworks, FrameworkGoTest)
+
+	units, err := getLoadUnits(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if changedFiles != nil {
+		units, err = affectedUnits(ctx, units, changedFiles, tests)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pkgs := []*packages.Package{}
+	for _, u := range units {
+		deps := []string{}
+		if cfg.CallGraph != CallGraphNone {
+			deps, err = getModuleDeps(ctx, u, tests)
+			if err != nil {
+				return nil, err
+			}
+		}
+		p, err := getASTpackages(ctx, u.dir, append(append([]string{}, u.patterns...), deps...), tests)
+		if err != nil {
+			return nil, err
+		}
+		// dependencies are only loaded for the call graph and they're reachable through Imports of selected packages
+		isDep := map[string]bool{}
+		for _, d := range deps {
+			isDep[d] = true
+		}
+		pkgs = append(pkgs, filter(p, func(p *packages.Package) bool {
+			return !isDep[strings.TrimSuffix(strings.TrimSuffix(p.PkgPath, ".test"), "_test")]
+		})...)
+	}
+	return selectTestVariants(pkgs), nil
+}
+
+// getModuleDeps returns import paths of packages that belong to the analyzed modules (main modules, or modules
+// of go.work) and are imported, directly or not, by packages of the unit. Only names and imports are loaded for that.
+func getModuleDeps(ctx context.Context, u loadUnit, tests bool) ([]string, error) {
+	cfg := packages.Config{
+		Mode:    packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedModule,
+		Context: ctx,
+		Dir:     u.dir,
+		Tests:   tests,
+	}
+	roots, err := packages.Load(&cfg, u.patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("packages.Load failed: %w", err)
+	}
+
+	isRoot := map[string]bool{}
+	for _, r := range roots {
+		isRoot[r.PkgPath] = true
+	}
+	deps := map[string]bool{}
+	packages.Visit(roots, nil, func(p *packages.Package) {
+		if p.Module != nil && p.Module.Main && !isRoot[p.PkgPath] && !strings.HasSuffix(p.PkgPath, ".test") {
+			deps[p.PkgPath] = true
+		}
+	})
+	res := make([]string, 0, len(deps))
+	for d := range deps {
+		res = append(res, d)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// packageLoader loads syntax of packages on demand, e.g. of a dependency containing a function
+// whose body is needed to resolve API Groups, and API Groups of API packages. It's safe for concurrent use.
+type packageLoader struct {
+	ctx context.Context
+
+	mu     sync.Mutex
+	pkgs   map[lazyPackageKey]*lazyPackage
+	groups map[lazyPackageKey]*lazyGroup
+}
+
+type lazyPackageKey struct {
+	// dir from which the package is loaded, so it's resolved within the same module as the importer
+	dir  string
+	path string
+}
+
+type lazyPackage struct {
+	once sync.Once
+	pkg  *packages.Package
+	err  error
+}
+
+type lazyGroup struct {
+	once  sync.Once
+	group string
+	err   error
+}
+
+func newPackageLoader(ctx context.Context) *packageLoader {
+	return &packageLoader{ctx: ctx, pkgs: map[lazyPackageKey]*lazyPackage{}, groups: map[lazyPackageKey]*lazyGroup{}}
+}
+
+// withSyntax returns pkg if its syntax is loaded, otherwise the package is loaded from source
+// in context of the importer (i.e. the package that imports it).
+func (l *packageLoader) withSyntax(importer, pkg *packages.Package) (*packages.Package, error) {
+	if len(pkg.Syntax) != 0 {
+		return pkg, nil
+	}
+	if l == nil {
+		return nil, fmt.Errorf("syntax of package %s is not loaded", pkg.PkgPath)
+	}
+
+	key := lazyPackageKey{dir: packageDir(importer), path: pkg.PkgPath}
+	l.mu.Lock()
+	lp, ok := l.pkgs[key]
+	if !ok {
+		lp = &lazyPackage{}
+		l.pkgs[key] = lp
+	}
+	l.mu.Unlock()
+
+	lp.once.Do(func() {
+		var pkgs []*packages.Package
+		pkgs, lp.err = getASTpackages(l.ctx, key.dir, []string{key.path}, false)
+		if lp.err != nil {
+			return
+		}
+		if len(pkgs) != 1 || len(pkgs[0].Errors) != 0 || len(pkgs[0].Syntax) == 0 {
+			lp.err = fmt.Errorf("failed to load syntax of package %s", key.path)
+			return
+		}
+		lp.pkg = pkgs[0]
+	})
+	return lp.pkg, lp.err
+}
+
+// groupName returns value of GroupName declared by the API package (k8s.io/api/apps/v1, github.com/openshift/api/config/v1)
+// which the importer depends on. Types of indirect dependencies come from export data of packages referencing them,
+// which contains only objects they use, and OpenShift declares GroupName as a variable, so unless the constant
+// is known to type checker, the declaration is found in files of the package.
+func (l *packageLoader) groupName(importer *packages.Package, api *types.Package) (string, error) {
+	if g, ok := groupNameConst(api); ok {
+		return g, nil
+	}
+	if l == nil {
+		return "", fmt.Errorf("GroupName of package %s is not loaded", api.Path())
+	}
+
+	key := lazyPackageKey{dir: packageDir(importer), path: api.Path()}
+	l.mu.Lock()
+	lg, ok := l.groups[key]
+	if !ok {
+		lg = &lazyGroup{}
+		l.groups[key] = lg
+	}
+	l.mu.Unlock()
+
+	lg.once.Do(func() {
+		cfg := packages.Config{Mode: packages.NeedName | packages.NeedFiles, Context: l.ctx, Dir: key.dir}
+		pkgs, err := packages.Load(&cfg, key.path)
+		if err != nil {
+			lg.err = fmt.Errorf("packages.Load failed: %w", err)
+			return
+		}
+		if len(pkgs) != 1 || len(pkgs[0].Errors) != 0 {
+			lg.err = fmt.Errorf("failed to load files of package %s", key.path)
+			return
+		}
+		lg.group, lg.err = declaredGroupName(pkgs[0].GoFiles)
+		if lg.err != nil {
+			lg.err = fmt.Errorf("package %s: %w", key.path, lg.err)
+		}
+	})
+	return lg.group, lg.err
+}
+
+// declaredGroupName evaluates package-level `GroupName` (constant or variable) declared in the files, or Group
+// of `SchemeGroupVersion = schema.GroupVersion{Group: groupName, ...}` if the package doesn't export GroupName.
+// Only files mentioning them are parsed.
+func declaredGroupName(files []string) (string, error) {
+	fset := token.NewFileSet()
+	values := map[string]ast.Expr{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		if !bytes.Contains(data, []byte("GroupName")) && !bytes.Contains(data, []byte("SchemeGroupVersion")) {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, data, parser.SkipObjectResolution)
+		if err != nil {
+			return "", err
+		}
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.CONST && gd.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for idx, name := range vs.Names {
+					if idx < len(vs.Values) {
+						values[name.Name] = vs.Values[idx]
+					}
+				}
+			}
+		}
+	}
+
+	// depth limits following of identifiers, in case of cycles
+	var eval func(e ast.Expr, depth int) (string, bool)
+	eval = func(e ast.Expr, depth int) (string, bool) {
+		if depth == 0 {
+			return "", false
+		}
+		switch e := e.(type) {
+		case *ast.BasicLit:
+			if e.Kind == token.STRING {
+				s, err := strconv.Unquote(e.Value)
+				return s, err == nil
+			}
+		case *ast.Ident:
+			if v, ok := values[e.Name]; ok {
+				return eval(v, depth-1)
+			}
+		case *ast.CompositeLit:
+			// schema.GroupVersion{Group: groupName, Version: "v1"} or schema.GroupVersion{groupName, "v1"}
+			for idx, elt := range e.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Group" {
+						return eval(kv.Value, depth-1)
+					}
+				} else if idx == 0 {
+					return eval(elt, depth-1)
+				}
+			}
+		}
+		return "", false
+	}
+	for _, name := range []string{"GroupName", "SchemeGroupVersion"} {
+		if v, ok := values[name]; ok {
+			if g, ok := eval(v, 10); ok {
+				return g, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("GroupName declaration not found")
+}
+
+// loaded returns number of packages loaded on demand
+func (l *packageLoader) loaded() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := 0
+	for _, lp := range l.pkgs {
+		if lp.pkg != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// loadError is a failure to load a package, raised as panic by the investigator
+type loadError struct{ error }
+
+// packageDir returns module's root dir of the package, or the dir of its files if module is not known
+func packageDir(pkg *packages.Package) string {
+	if pkg.Module != nil && pkg.Module.Dir != "" {
+		return pkg.Module.Dir
+	}
+	if len(pkg.GoFiles) != 0 {
+		return filepath.Dir(pkg.GoFiles[0])
+	}
+	return ""
+}
+
+func getLoadUnits(cfg Config) ([]loadUnit, error) {
+	if len(cfg.Patterns) != 0 {
+		return patternLoadUnits(cfg), nil
+	}
+
+	dirs := []string{}
+	for _, root := range cfg.includeRoots() {
+		d, err := getDirsToScan(filepath.Join(cfg.RepoPath, root), cfg.excludes(), cfg.DirFilter)
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, d...)
+	}
+
+	byModule := map[string][]string{}
+	for _, dir := range dirs {
+		mod := findModuleRoot(cfg.RepoPath, dir)
+		byModule[mod] = append(byModule[mod], dir)
+	}
+	return sortedLoadUnits(byModule), nil
+}
+
+// patternLoadUnits splits patterns by modules containing them.
+// Patterns which aren't paths (e.g. import paths) are loaded within RepoPath.
+func patternLoadUnits(cfg Config) []loadUnit {
+	byModule := map[string][]string{}
+	for _, p := range cfg.Patterns {
+		mod := cfg.RepoPath
+		if build.IsLocalImport(p) || filepath.IsAbs(p) {
+			if !filepath.IsAbs(p) {
+				p = filepath.Join(cfg.RepoPath, p)
+			}
+			dir, _, _ := strings.Cut(p, "...")
+			mod = findModuleRoot(cfg.RepoPath, dir)
+		}
+		byModule[mod] = append(byModule[mod], p)
+	}
+	return sortedLoadUnits(byModule)
+}
+
+// sortedLoadUnits returns load unit of each module, ordered by module's dir
+func sortedLoadUnits(byModule map[string][]string) []loadUnit {
+	modules := make([]string, 0, len(byModule))
+	for mod := range byModule {
+		modules = append(modules, mod)
+	}
+	sort.Strings(modules)
+
+	units := []loadUnit{}
+	for _, mod := range modules {
+		units = append(units, loadUnit{dir: mod, patterns: byModule[mod]})
+	}
+	return units
+}
+
+// selectTestVariants deduplicates packages loaded with packages.Config.Tests:
+// if package has a test variant (containing also _test.go files), only that variant is kept,
+// and generated test mains (PKG.test) are dropped.
+func selectTestVariants(pkgs []*packages.Package) []*packages.Package {
+	isTestVariant := func(p *packages.Package) bool {
+		return strings.HasSuffix(p.ID, ".test]")
+	}
+	hasTestVariant := map[string]bool{}
+	for _, p := range pkgs {
+		if isTestVariant(p) && p.PkgPath != "" {
+			hasTestVariant[p.PkgPath] = true
+		}
+	}
+	return filter(pkgs, func(p *packages.Package) bool {
+		if strings.HasSuffix(p.PkgPath, ".test") && p.Name == "main" {
+			return false
+		}
+		return isTestVariant(p) || !hasTestVariant[p.PkgPath]
+	})
+}
+
+func checkIfPathExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// findModuleRoot returns the closest directory containing go.mod, starting from dir and going up until repoPath.
+// If none is found, repoPath is returned.
+func findModuleRoot(repoPath, dir string) string {
+	repoPath = filepath.Clean(repoPath)
+	for d := filepath.Clean(dir); ; d = filepath.Dir(d) {
+		if exists, _ := checkIfPathExists(filepath.Join(d, "go.mod")); exists {
+			return d
+		}
+		if d == repoPath || d == filepath.Dir(d) {
+			return repoPath
+		}
+	}
+}
+
+func getDirsToScan(root string, excludes []string, filter *regexp.Regexp) ([]string, error) {
+	res := []string{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		for _, ex := range excludes {
+			if matched, _ := filepath.Match(ex, d.Name()); matched {
+				return fs.SkipDir
+			}
+		}
+		if filter == nil || filter.MatchString(path) {
+			res = append(res, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error when walking dir tree %s: %w", root, err)
+	}
+	return res, nil
+}
+
+func getASTpackages(ctx context.Context, dir string, pkgs []string, tests bool) ([]*packages.Package, error) {
+	astCfg := packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles |
+			packages.NeedImports | packages.NeedTypes | packages.NeedTypesSizes |
+			packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedModule,
+		Context: ctx,
+		Dir:     dir,
+		Tests:   tests,
+		//BuildFlags: []string{"-N", "-l"},
+	}
+	ppkgs, err := packages.Load(&astCfg, pkgs...)
+	if err != nil {
+		return nil, fmt.Errorf("packages.Load failed: %w", err)
+	}
+	return ppkgs, nil
+}
@@ -0,0 +1,78 @@
+package apiusage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestOnDemandLoading(t *testing.T) {
+	full, err := Analyze(context.Background(), Config{RepoPath: testDataPath, IncludeRoots: []string{"test/extended"}})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	// other_pkg contains helper returning GVRs, so it has to be loaded on demand
+	lazy, err := Analyze(context.Background(), Config{
+		RepoPath:     testDataPath,
+		IncludeRoots: []string{"test/extended"},
+		Excludes:     []string{"other_pkg"},
+		Stats:        true,
+	})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if lazy.Stats.OnDemandPackages != 1 {
+		t.Errorf("expected 1 package loaded on demand, got %d", lazy.Stats.OnDemandPackages)
+	}
+	lazy.Stats = nil
+	if !reflect.DeepEqual(full, lazy) {
+		t.Errorf("reports differ when helper package is loaded on demand")
+	}
+}
+
+func TestDeclaredGroupName(t *testing.T) {
+	expected := map[string]string{
+		// k8s.io/api
+		"const GroupName = \"apps\"": "apps",
+		// github.com/openshift/api
+		"var (\n\tGroupName = \"config.openshift.io\"\n\tGroupVersion = schema.GroupVersion{Group: GroupName, Version: \"v1\"}\n)": "config.openshift.io",
+		// github.com/openshift/api/imageregistry/v1
+		"const groupName = \"imageregistry.operator.openshift.io\"\nvar SchemeGroupVersion = schema.GroupVersion{Group: groupName, Version: \"v1\"}": "imageregistry.operator.openshift.io",
+	}
+	for src, e := range expected {
+		file := filepath.Join(t.TempDir(), "register.go")
+		if err := os.WriteFile(file, []byte("package v1\n\n"+src+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if actual, err := declaredGroupName([]string{file}); err != nil || actual != e {
+			t.Errorf("%q: expected %q, got %q (%v)", src, e, actual, err)
+		}
+	}
+}
+
+func TestPatternsOfNestedModule(t *testing.T) {
+	units := patternLoadUnits(Config{RepoPath: testDataPath, Patterns: []string{"./test/e2e/...", "./test/nested_module/...", "k8s.io/client-go/dynamic"}})
+	nested := filepath.Join(testDataPath, "test/nested_module")
+	if len(units) != 2 || units[0].dir != testDataPath || units[1].dir != nested {
+		t.Fatalf("expected load units of %q and %q, got %+v", testDataPath, nested, units)
+	}
+
+	report, err := Analyze(context.Background(), Config{RepoPath: testDataPath, Patterns: []string{"./test/nested_module/..."}})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	groups := map[string][]string{}
+	for _, test := range report.Tests {
+		groups[test.Name] = test.APIGroups
+	}
+	expected := map[string][]string{
+		"TestNestedModule": {"f2a1.openshift.io"},
+		"TestNestedModule/resource_of_nested_module_[apigroup:f2a1.openshift.io]": {"f2a1.openshift.io"},
+	}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("expected %v, got %v", expected, groups)
+	}
+}
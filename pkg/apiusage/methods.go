@@ -0,0 +1,155 @@
+package apiusage
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// callFunction returns API Groups of GVRs returned by the called function or method as result with given index.
+// Calls of interface methods are dispatched to the method of type argument if receiver's type is a type parameter
+// bound by analyzed generic function, or to methods of all implementations otherwise.
+func (i *investigator) callFunction(fun ast.Expr, funId *ast.Ident, f *types.Func, result int) []string {
+	if recv := f.Type().(*types.Signature).Recv(); recv != nil && types.IsInterface(recv.Type()) {
+		return i.dispatch(fun, f, result)
+	}
+	i2, fd := i.funcDecl(f)
+	return i2.withTypeArgs(i.typeArguments(funId, f)).analyzeFunction(fd, result)
+}
+
+// dispatch returns API Groups of GVRs returned by methods which can be called by the interface method call: p.GVR()
+func (i *investigator) dispatch(fun ast.Expr, m *types.Func, result int) []string {
+	sel, ok := ast.Unparen(fun).(*ast.SelectorExpr)
+	if !ok {
+		// method expression or value
+		i.unsupported(fun)
+	}
+
+	methods := []*types.Func{}
+	recvType := i.concreteType(i.pkg.TypesInfo.TypeOf(sel.X))
+	if types.IsInterface(recvType) {
+		methods = i.implementations(recvType.Underlying().(*types.Interface), m.Name())
+	} else if impl, ok := lookupMethod(recvType, m); ok {
+		methods = append(methods, impl)
+	}
+	if len(methods) > 1 {
+		i.markMay()
+	}
+
+	groups := []string{}
+	for _, impl := range methods {
+		i2, fd := i.funcDecl(impl)
+		groups = append(groups, i2.withTypeArgs(nil).analyzeFunction(fd, result)...)
+	}
+	return groups
+}
+
+// lookupMethod returns method of the type with the same name as m
+func lookupMethod(t types.Type, m *types.Func) (*types.Func, bool) {
+	obj, _, _ := types.LookupFieldOrMethod(t, true, m.Pkg(), m.Name())
+	f, ok := obj.(*types.Func)
+	return f, ok
+}
+
+// implementations returns methods with the name of all named types implementing the interface
+// which are declared in packages of the module imported (directly or not) by analyzed package, or in the package itself.
+func (i *investigator) implementations(iface *types.Interface, name string) []*types.Func {
+	modulePath := i.pkg.PkgPath
+	if i.pkg.Module != nil {
+		modulePath = i.pkg.Module.Path
+	}
+	inModule := func(p *types.Package) bool {
+		return p == i.pkg.Types || p.Path() == modulePath || strings.HasPrefix(p.Path(), modulePath+"/")
+	}
+
+	methods := []*types.Func{}
+	seen := map[*types.Package]bool{}
+	var visit func(p *types.Package)
+	visit = func(p *types.Package) {
+		if seen[p] || !inModule(p) {
+			return
+		}
+		seen[p] = true
+		for _, n := range p.Scope().Names() {
+			tn, ok := p.Scope().Lookup(n).(*types.TypeName)
+			if !ok || tn.IsAlias() {
+				continue
+			}
+			named, ok := tn.Type().(*types.Named)
+			if !ok || types.IsInterface(named) || named.TypeParams().Len() != 0 {
+				continue
+			}
+			for _, t := range []types.Type{named, types.NewPointer(named)} {
+				if !types.Implements(t, iface) {
+					continue
+				}
+				obj, _, _ := types.LookupFieldOrMethod(t, true, p, name)
+				if m, ok := obj.(*types.Func); ok {
+					methods = append(methods, m)
+				}
+				break
+			}
+		}
+		for _, imp := range p.Imports() {
+			visit(imp)
+		}
+	}
+	visit(i.pkg.Types)
+	return methods
+}
+
+// typeArguments returns type arguments of generic function or method called through the identifier,
+// by names of type parameters. Type parameters are matched by name, as the function might be declared
+// in package loaded on demand, with its own type parameter objects.
+func (i *investigator) typeArguments(funId *ast.Ident, f *types.Func) map[string]types.Type {
+	args := map[string]types.Type{}
+	bind := func(params *types.TypeParamList, targs *types.TypeList) {
+		for k := 0; k < params.Len() && k < targs.Len(); k++ {
+			args[params.At(k).Obj().Name()] = i.concreteType(targs.At(k))
+		}
+	}
+
+	sig := f.Origin().Type().(*types.Signature)
+	if inst, ok := i.pkg.TypesInfo.Instances[funId]; ok {
+		// F[T](), or F() with inferred type arguments
+		bind(sig.TypeParams(), inst.TypeArgs)
+	}
+	if recv := f.Type().(*types.Signature).Recv(); recv != nil {
+		// method of instantiated generic type: h.GVR() where h is Holder[T]
+		if named, ok := deref(recv.Type()).(*types.Named); ok {
+			bind(sig.RecvTypeParams(), named.TypeArgs())
+		}
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	return args
+}
+
+// concreteType returns type argument of type parameter bound by analyzed generic function, or the type itself
+func (i *investigator) concreteType(t types.Type) types.Type {
+	if tp, ok := t.(*types.TypeParam); ok {
+		if arg, ok := i.typeArgs[tp.Obj().Name()]; ok {
+			return arg
+		}
+	}
+	return t
+}
+
+// withTypeArgs returns investigator analyzing function with given type arguments, sharing state with i
+func (i *investigator) withTypeArgs(args map[string]types.Type) *investigator {
+	i2 := *i
+	i2.typeArgs = args
+	return &i2
+}
+
+// typeArgsKey identifies type arguments of analyzed generic function in funcSummaryKey
+func (i *investigator) typeArgsKey() string {
+	args := []string{}
+	for name, t := range i.typeArgs {
+		args = append(args, name+"="+types.TypeString(t, nil))
+	}
+	sort.Strings(args)
+	return strings.Join(args, ",")
+}
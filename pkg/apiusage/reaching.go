@@ -0,0 +1,262 @@
+package apiusage
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/cfg"
+)
+
+// definition is a statement giving a local variable its value (or value of its Group field)
+type definition struct {
+	// node is *ast.AssignStmt, *ast.ValueSpec or *ast.Field (function parameter or named result).
+	// Variables of range statement get *ast.AssignStmt (see rangeAssignStmt).
+	node ast.Node
+	// index of the variable among variables assigned by the node
+	index int
+	// pos is a position of the variable within the node
+	pos token.Pos
+	// field is true for writes of Group field: gvr.Group = "g"
+	field bool
+	// closure is true for definitions inside function literal nested in function declaring the variable
+	closure bool
+	// result is true for named result of the function, which is zero on entry
+	result bool
+}
+
+// zero returns true for declaration without value: var gvr GVR, or named result
+func (d definition) zero() bool {
+	vs, ok := d.node.(*ast.ValueSpec)
+	return ok && len(vs.Values) == 0 || d.result
+}
+
+// definitionsOf returns all definitions of the variable within the function body, in order of appearance
+func definitionsOf(info *types.Info, v *types.Var, body *ast.BlockStmt) []definition {
+	is := func(e ast.Expr) bool {
+		id, ok := e.(*ast.Ident)
+		return ok && info.ObjectOf(id) == v
+	}
+	isGroup := func(e ast.Expr) bool {
+		sel, ok := e.(*ast.SelectorExpr)
+		return ok && sel.Sel.Name == "Group" && is(sel.X)
+	}
+
+	defs := []definition{}
+	closures := 0
+	astutil.Apply(body, func(c *astutil.Cursor) bool {
+		switch n := c.Node().(type) {
+		case *ast.FuncLit:
+			closures++
+		case *ast.AssignStmt:
+			if n.Tok != token.ASSIGN && n.Tok != token.DEFINE {
+				return true
+			}
+			for idx, lhs := range n.Lhs {
+				if is(lhs) || isGroup(lhs) {
+					defs = append(defs, definition{node: n, index: idx, pos: lhs.Pos(), field: isGroup(lhs), closure: closures != 0})
+				}
+			}
+		case *ast.ValueSpec:
+			for idx, name := range n.Names {
+				if is(name) {
+					defs = append(defs, definition{node: n, index: idx, pos: name.Pos(), closure: closures != 0})
+				}
+			}
+		case *ast.RangeStmt:
+			rng := rangeAssignStmt(n)
+			for idx, lhs := range rng.Lhs {
+				if is(lhs) {
+					defs = append(defs, definition{node: rng, index: idx, pos: lhs.Pos(), closure: closures != 0})
+				}
+			}
+		}
+		return true
+	}, func(c *astutil.Cursor) bool {
+		if _, ok := c.Node().(*ast.FuncLit); ok {
+			closures--
+		}
+		return true
+	})
+	return defs
+}
+
+// enclosingFunc returns the type and body of innermost function declaration or literal enclosing the position
+func enclosingFunc(file *ast.File, pos token.Pos) (*ast.FuncType, *ast.BlockStmt) {
+	path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+	for _, n := range path {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return n.Type, n.Body
+		case *ast.FuncDecl:
+			return n.Type, n.Body
+		}
+	}
+	return nil, nil
+}
+
+// reachingDefinitions returns definitions of the local variable which can reach its use.
+// Definitions are found in the function declaring the variable, and the control flow graph
+// of the function is used to drop definitions overwritten on every path to the use.
+// Definitions made by closures are assumed to reach any use, and so are all definitions
+// if the variable is used by a closure.
+// Declaration without value is dropped if any other definition reaches the use.
+func (i *investigator) reachingDefinitions(use *ast.Ident, v *types.Var) []definition {
+	typ, body := enclosingFunc(i.root, v.Pos())
+	if body == nil {
+		return nil
+	}
+	defs := definitionsOf(i.pkg.TypesInfo, v, body)
+	for _, fields := range []*ast.FieldList{typ.Params, typ.Results} {
+		if fields == nil || v.Pos() < fields.Pos() || fields.End() <= v.Pos() {
+			continue
+		}
+		// function parameter or named result, defined on entry
+		for _, f := range fields.List {
+			for idx, name := range f.Names {
+				if name.Pos() == v.Pos() {
+					defs = append([]definition{{node: f, index: idx, pos: name.Pos(), result: fields == typ.Results}}, defs...)
+				}
+			}
+		}
+	}
+
+	reaching := i.flowDefinitions(defs, use, body)
+	if reaching == nil {
+		reaching = defs
+	}
+	nonZero := []definition{}
+	for _, d := range reaching {
+		if !d.zero() {
+			nonZero = append(nonZero, d)
+		}
+	}
+	if len(nonZero) != 0 {
+		return nonZero
+	}
+	return reaching
+}
+
+// flowDefinitions computes definitions reaching the use within the function body,
+// or returns nil if the use is not part of the body's control flow (e.g. it's inside a closure).
+func (i *investigator) flowDefinitions(defs []definition, use *ast.Ident, body *ast.BlockStmt) []definition {
+	if _, useBody := enclosingFunc(i.root, use.Pos()); useBody != body {
+		return nil
+	}
+
+	g := cfg.New(body, func(*ast.CallExpr) bool { return true })
+	contains := func(n ast.Node, pos token.Pos) bool { return n.Pos() <= pos && pos < n.End() }
+
+	// defined[b] lists indexes of definitions made in the block b, with index of the node making them
+	type blockDef struct{ node, def int }
+	defined := map[*cfg.Block][]blockDef{}
+	useBlock, useNode := (*cfg.Block)(nil), 0
+	for _, b := range g.Blocks {
+		for ni, n := range b.Nodes {
+			for di, d := range defs {
+				if !d.closure && d.node != nil && contains(n, d.pos) {
+					defined[b] = append(defined[b], blockDef{node: ni, def: di})
+				}
+			}
+			if contains(n, use.Pos()) {
+				useBlock, useNode = b, ni
+			}
+		}
+	}
+	if useBlock == nil {
+		return nil
+	}
+
+	// out[b] is a set of definitions live at the end of the block b
+	preds := map[*cfg.Block][]*cfg.Block{}
+	for _, b := range g.Blocks {
+		for _, s := range b.Succs {
+			preds[s] = append(preds[s], b)
+		}
+	}
+	entry := map[int]bool{}
+	for di, d := range defs {
+		if _, ok := d.node.(*ast.Field); ok {
+			entry[di] = true
+		}
+	}
+	in := func(b *cfg.Block, out map[*cfg.Block]map[int]bool) map[int]bool {
+		res := map[int]bool{}
+		if b == g.Blocks[0] {
+			for di := range entry {
+				res[di] = true
+			}
+		}
+		for _, p := range preds[b] {
+			for di := range out[p] {
+				res[di] = true
+			}
+		}
+		return res
+	}
+	out := map[*cfg.Block]map[int]bool{}
+	for changed := true; changed; {
+		changed = false
+		for _, b := range g.Blocks {
+			res := map[int]bool{}
+			if bd := defined[b]; len(bd) != 0 {
+				res[bd[len(bd)-1].def] = true
+			} else {
+				res = in(b, out)
+			}
+			if len(res) != len(out[b]) {
+				out[b] = res
+				changed = true
+			}
+		}
+	}
+
+	reaching := map[int]bool{}
+	for _, bd := range defined[useBlock] {
+		if bd.node < useNode {
+			reaching = map[int]bool{bd.def: true}
+		}
+	}
+	if len(reaching) == 0 {
+		reaching = in(useBlock, out)
+	}
+	for di, d := range defs {
+		if d.closure {
+			reaching[di] = true
+		}
+	}
+
+	idxs := []int{}
+	for di := range reaching {
+		idxs = append(idxs, di)
+	}
+	sort.Ints(idxs)
+	res := []definition{}
+	for _, di := range idxs {
+		res = append(res, defs[di])
+	}
+	return res
+}
+
+// definitionValue returns API Groups of the value given to a variable by the definition
+func (i *investigator) definitionValue(d definition) []string {
+	switch node := d.node.(type) {
+	case *ast.AssignStmt:
+		if d.field {
+			// gvr.Group = "g"
+			i.explain(node)
+			value, result := assignedValue(len(node.Lhs), node.Rhs, d.index)
+			return i.analyzeValue(value, result)
+		}
+		return i.assignStmt(node, d.index)
+	case *ast.ValueSpec:
+		return i.valueSpec(node, d.index)
+	case *ast.Field:
+		return i.paramValue(node)
+	default:
+		i.unsupported(d.node)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package apiusage
+
+import (
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Report is a result of analysis of all scanned packages.
+// Paths of files within analyzed repository are relative to Config.RepoPath.
+type Report struct {
+	// Tests lists every discovered test, including tests for which no API call site was found.
+	Tests []TestUsage `json:"tests"`
+
+	// Unattributed lists call sites that couldn't be linked to any test,
+	// e.g. call sites inside helper functions.
+	Unattributed []CallSite `json:"unattributed,omitempty"`
+
+	// Diagnostics lists problems encountered during analysis, like unsupported constructs.
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+
+	// Stats contains measurements of the analysis, only if enabled by Config.Stats.
+	Stats *Stats `json:"stats,omitempty"`
+}
+
+// TestUsage describes API usage of a single test.
+type TestUsage struct {
+	// Name is a full name of the test, i.e. texts of all enclosing containers and the spec joined with space.
+	Name      string         `json:"name"`
+	Framework Framework      `json:"framework"`
+	Package   string         `json:"package"`
+	Position  token.Position `json:"position"`
+
+	// APIGroups is a sorted and deduplicated union of API Groups of all CallSites.
+	APIGroups []string `json:"apiGroups"`
+	// GroupClasses maps each of APIGroups to its class.
+	GroupClasses map[string]GroupClass `json:"groupClasses,omitempty"`
+	CallSites    []CallSite            `json:"callSites"`
+}
+
+// CallSite is a single place in the code where API is accessed.
+type CallSite struct {
+	Position  token.Position `json:"position"`
+	APIGroups []string       `json:"apiGroups"`
+	// GroupClasses maps each of APIGroups to its class.
+	GroupClasses map[string]GroupClass `json:"groupClasses,omitempty"`
+	// Resources lists resources accessed by the call site, if known (e.g. parsed from REST path).
+	// Like APIGroups, they're filtered by Config.Groups.
+	Resources []Resource `json:"resources,omitempty"`
+	// May is true if APIGroups come from different definitions of a variable which can reach the call site,
+	// e.g. GVR assigned in branches of if statement, so call site may use only some of them.
+	May bool `json:"may,omitempty"`
+
+	// Via is a path of calls leading from the test to the function containing the call site.
+	// It is empty if call site is lexically inside the test.
+	Via []CallEdge `json:"via,omitempty"`
+
+	// Explanation lists code visited when resolving API Groups, starting with the call site itself.
+	// It is only set if Config.Explain is true.
+	Explanation []ResolutionStep `json:"explanation,omitempty"`
+}
+
+// Resource is a resource (or just API Group) of the API accessed by a call site.
+type Resource struct {
+	Group       string `json:"group"`
+	Version     string `json:"version,omitempty"`
+	Resource    string `json:"resource,omitempty"`
+	Subresource string `json:"subresource,omitempty"`
+	// Verb is set if it's known how the resource is accessed, e.g. get, list, create.
+	Verb string `json:"verb,omitempty"`
+}
+
+// String returns the resource as a path: GROUP/VERSION/RESOURCE/SUBRESOURCE, core API Group is written as "core".
+// Verb, if known, precedes the path.
+func (r Resource) String() string {
+	group := r.Group
+	if group == "" {
+		group = coreGroupName
+	}
+	s := strings.Join(filter([]string{group, r.Version, r.Resource, r.Subresource}, func(s string) bool { return s != "" }), "/")
+	if r.Verb != "" {
+		return r.Verb + " " + s
+	}
+	return s
+}
+
+// CallEdge is a call of Callee function made from Caller function.
+type CallEdge struct {
+	Caller   string         `json:"caller"`
+	Callee   string         `json:"callee"`
+	Position token.Position `json:"position"`
+}
+
+// Diagnostic describes an issue that prevented full analysis of particular piece of code.
+type Diagnostic struct {
+	Position token.Position `json:"position"`
+	Message  string         `json:"message"`
+	// Construct is position of the unsupported construct, which can be in another function or package
+	// than the call site at Position.
+	Construct *token.Position `json:"construct,omitempty"`
+}
+
+func (t *TestUsage) addCallSite(cs CallSite) {
+	t.CallSites = append(t.CallSites, cs)
+	t.APIGroups = uniqueSorted(append(t.APIGroups, cs.APIGroups...))
+	if t.GroupClasses == nil {
+		t.GroupClasses = map[string]GroupClass{}
+	}
+	for g, c := range cs.GroupClasses {
+		t.GroupClasses[g] = c
+	}
+}
+
+func uniqueSorted(xs []string) []string {
+	set := map[string]struct{}{}
+	for _, x := range xs {
+		set[x] = struct{}{}
+	}
+	res := make([]string, 0, len(set))
+	for x := range set {
+		res = append(res, x)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func lessPosition(a, b token.Position) bool {
+	if a.Filename != b.Filename {
+		return a.Filename < b.Filename
+	}
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Column < b.Column
+}
+
+func (r *Report) sort() {
+	sort.SliceStable(r.Tests, func(i, j int) bool {
+		if r.Tests[i].Position != r.Tests[j].Position {
+			return lessPosition(r.Tests[i].Position, r.Tests[j].Position)
+		}
+		return r.Tests[i].Name < r.Tests[j].Name
+	})
+	for _, t := range r.Tests {
+		sort.SliceStable(t.CallSites, func(i, j int) bool {
+			return lessPosition(t.CallSites[i].Position, t.CallSites[j].Position)
+		})
+	}
+	sort.SliceStable(r.Unattributed, func(i, j int) bool {
+		return lessPosition(r.Unattributed[i].Position, r.Unattributed[j].Position)
+	})
+	sort.SliceStable(r.Diagnostics, func(i, j int) bool {
+		if r.Diagnostics[i].Position == r.Diagnostics[j].Position {
+			return r.Diagnostics[i].Message < r.Diagnostics[j].Message
+		}
+		return lessPosition(r.Diagnostics[i].Position, r.Diagnostics[j].Position)
+	})
+}
+
+// relativize makes file paths of all positions in the report relative to the root,
+// so reports of different checkouts of the same repository can be compared.
+// Files outside the root are left untouched.
+func (r *Report) relativize(root string) {
+	rel := func(p *token.Position) {
+		if !filepath.IsAbs(p.Filename) {
+			return
+		}
+		if rp, err := filepath.Rel(root, p.Filename); err == nil && !strings.HasPrefix(rp, "..") {
+			p.Filename = rp
+		}
+	}
+	relCallSite := func(cs *CallSite) {
+		rel(&cs.Position)
+		for i := range cs.Via {
+			rel(&cs.Via[i].Position)
+		}
+		for i := range cs.Explanation {
+			rel(&cs.Explanation[i].Position)
+		}
+	}
+
+	for i := range r.Tests {
+		rel(&r.Tests[i].Position)
+		for j := range r.Tests[i].CallSites {
+			relCallSite(&r.Tests[i].CallSites[j])
+		}
+	}
+	for i := range r.Unattributed {
+		relCallSite(&r.Unattributed[i])
+	}
+	for i := range r.Diagnostics {
+		rel(&r.Diagnostics[i].Position)
+		if r.Diagnostics[i].Construct != nil {
+			rel(r.Diagnostics[i].Construct)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package apiusage
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"path"
+	"sort"
+	"strings"
+)
+
+// unknownValue stands for a part of a string that couldn't be resolved, e.g. namespace passed as a parameter
+// to the function building REST path. API Groups containing it are dropped.
+const unknownValue = "<unknown>"
+
+// namespaceSubresources are subresources of namespaces: /api/v1/namespaces/NAME/status
+var namespaceSubresources = map[string]bool{"status": true, "finalize": true}
+
+// parseRESTPath parses path of API Group's resource: /apis/GROUP/VERSION/[namespaces/NS/]RESOURCE[/NAME[/SUBRESOURCE]],
+// or /api/VERSION/... of the core API Group. Paths of discovery (/apis/GROUP, /apis/GROUP/VERSION) give just the group.
+// It returns false for paths outside of API Groups (/healthz, /metrics, /apis) and for paths with unknown group.
+func parseRESTPath(p string) (Resource, bool) {
+	segs := restPathSegments(p)
+
+	r := Resource{}
+	switch {
+	case len(segs) >= 2 && segs[0] == "api":
+		r.Version, segs = segs[1], segs[2:]
+	case len(segs) >= 2 && segs[0] == "apis":
+		r.Group, segs = segs[1], segs[2:]
+		if len(segs) != 0 {
+			r.Version, segs = segs[0], segs[1:]
+		}
+	default:
+		return Resource{}, false
+	}
+	if strings.Contains(r.Group, unknownValue) {
+		return Resource{}, false
+	}
+
+	if len(segs) != 0 && segs[0] == "watch" {
+		// deprecated /apis/GROUP/VERSION/watch/RESOURCE
+		segs = segs[1:]
+	}
+	if len(segs) >= 3 && segs[0] == "namespaces" && !namespaceSubresources[segs[2]] {
+		// namespaced resource
+		segs = segs[2:]
+	}
+	if len(segs) != 0 && !strings.Contains(segs[0], unknownValue) {
+		r.Resource = segs[0]
+		if len(segs) >= 3 && !strings.Contains(segs[2], unknownValue) {
+			r.Subresource = segs[2]
+		}
+	}
+	return r, true
+}
+
+// restPathSegments returns segments of the path without query and fragment
+func restPathSegments(p string) []string {
+	if idx := strings.IndexAny(p, "?#"); idx != -1 {
+		p = p[:idx]
+	}
+	return strings.FieldsFunc(p, func(r rune) bool { return r == '/' })
+}
+
+// isNonAPIPath checks if the path is known to be outside of API Groups: /healthz, /metrics, /apis.
+// Paths of unknown API Group (/apis/<unknown>/v1) or starting with unknown value are not.
+func isNonAPIPath(p string) bool {
+	if _, ok := parseRESTPath(p); ok {
+		return false
+	}
+	segs := restPathSegments(p)
+	if len(segs) == 0 {
+		return true
+	}
+	if strings.Contains(segs[0], unknownValue) {
+		return false
+	}
+	return segs[0] != "api" && segs[0] != "apis" || len(segs) == 1
+}
+
+// product returns results of f for every pair of xs and ys
+func product(xs, ys []string, f func(x, y string) string) []string {
+	res := []string{}
+	for _, x := range xs {
+		for _, y := range ys {
+			res = append(res, f(x, y))
+		}
+	}
+	return res
+}
+
+func concat(x, y string) string {
+	return x + y
+}
+
+// stringValues returns values of string expression, or unknownValue if none is found
+func (i *investigator) stringValues(e ast.Expr) []string {
+	if values := i.analyzeExpr(e); len(values) != 0 {
+		return uniqueSorted(values)
+	}
+	return []string{unknownValue}
+}
+
+// concatenation returns values of string concatenation: "/apis/" + group + "/v1", prefix + ".openshift.io"
+func (i *investigator) concatenation(e *ast.BinaryExpr) []string {
+	if v := i.pkg.TypesInfo.Types[e].Value; v != nil && v.Kind() == constant.String {
+		return []string{constant.StringVal(v)}
+	}
+	i.explain(e)
+	return product(i.stringValues(e.X), i.stringValues(e.Y), concat)
+}
+
+// isPathJoin checks if the call is a call of path.Join
+func isPathJoin(info *types.Info, ce *ast.CallExpr) bool {
+	id := calleeIdent(ce)
+	if id == nil {
+		return false
+	}
+	f, ok := info.Uses[id].(*types.Func)
+	return ok && f.Pkg() != nil && f.Pkg().Path() == "path" && f.Name() == "Join"
+}
+
+// joinedPaths returns values of path joined from the segments like path.Join does
+func (i *investigator) joinedPaths(ce *ast.CallExpr) []string {
+	if ce.Ellipsis.IsValid() {
+		// path.Join(segments...)
+		i.unsupported(ce)
+	}
+	paths := []string{""}
+	for _, arg := range ce.Args {
+		paths = product(paths, i.stringValues(arg), func(x, y string) string { return path.Join(x, y) })
+	}
+	return paths
+}
+
+// restPathCall returns API Groups of REST path passed to the call: AbsPath("/apis", group, "v1"), RequestURI(uri)
+func (i *investigator) restPathCall(call *ast.CallExpr) []string {
+	i.explain(call)
+	return i.restPaths(i.joinedPaths(call))
+}
+
+// rawCLICall returns API Groups of REST path passed to oc with --raw flag:
+// oc.Run("get").Args("--raw", path), oc.Run("get").Args("--raw=/apis/...")
+func (i *investigator) rawCLICall(call *ast.CallExpr) []string {
+	i.explain(call)
+	idx := rawFlagIndex(i.pkg.TypesInfo, call)
+	if flag := call.Args[idx]; constantPrefix(i.pkg.TypesInfo, flag) != "--raw" {
+		// --raw=PATH
+		paths := []string{}
+		for _, v := range i.stringValues(flag) {
+			paths = append(paths, strings.TrimPrefix(v, "--raw="))
+		}
+		return i.restPaths(paths)
+	}
+	if idx+1 == len(call.Args) {
+		// path passed by the next call of Args()
+		i.unsupported(call)
+	}
+	return i.restPaths(i.stringValues(call.Args[idx+1]))
+}
+
+// restPaths returns API Groups of REST paths, and records their resources. If all of the paths
+// are outside of API Groups (e.g. `"/healthz/" + name`), the call is marked as not being an API call.
+func (i *investigator) restPaths(paths []string) []string {
+	groups := []string{}
+	nonAPI := 0
+	for _, p := range paths {
+		r, ok := parseRESTPath(p)
+		if !ok {
+			if isNonAPIPath(p) {
+				nonAPI++
+			}
+			continue
+		}
+		groups = append(groups, r.Group)
+		if i.resources != nil {
+			*i.resources = append(*i.resources, r)
+		}
+	}
+	i.notAPICall = len(paths) != 0 && nonAPI == len(paths)
+	return groups
+}
+
+// uniqueSortedResources removes duplicates from resources and sorts them
+func uniqueSortedResources(rs []Resource) []Resource {
+	set := map[Resource]bool{}
+	res := []Resource{}
+	for _, r := range rs {
+		if !set[r] {
+			set[r] = true
+			res = append(res, r)
+		}
+	}
+	sort.Slice(res, func(a, b int) bool { return res[a].String() < res[b].String() })
+	return res
+}
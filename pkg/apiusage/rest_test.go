@@ -0,0 +1,46 @@
+package apiusage
+
+import "testing"
+
+func TestParseRESTPath(t *testing.T) {
+	expected := map[string]string{
+		"/apis/config.openshift.io/v1/clusteroperators":                      "config.openshift.io/v1/clusteroperators",
+		"/apis/config.openshift.io/v1/clusterversions/version/status":        "config.openshift.io/v1/clusterversions/status",
+		"/apis/route.openshift.io/v1/namespaces/ns/routes/name?timeout=1s":   "route.openshift.io/v1/routes",
+		"/apis/build.openshift.io/v1/namespaces/" + unknownValue + "/builds": "build.openshift.io/v1/builds",
+		"/apis/build.openshift.io/v1/watch/namespaces/ns/builds":             "build.openshift.io/v1/builds",
+		"/apis/image.openshift.io/v1/" + unknownValue:                        "image.openshift.io/v1",
+		"/apis/image.openshift.io":                                           "image.openshift.io",
+		"/api/v1/namespaces/ns/pods/name/log":                                "core/v1/pods/log",
+		"/api/v1/namespaces/ns/status":                                       "core/v1/namespaces/status",
+		"/api/v1/namespaces/ns":                                              "core/v1/namespaces",
+		"/healthz":                                                           "",
+		"/apis":                                                              "",
+		"/apis/" + unknownValue + "/v1/clusteroperators":                     "",
+	}
+	for p, e := range expected {
+		r, ok := parseRESTPath(p)
+		if actual := r.String(); ok != (e != "") || ok && actual != e {
+			t.Errorf("%q: expected %q, got %q (%v)", p, e, actual, ok)
+		}
+	}
+}
+
+func TestIsNonAPIPath(t *testing.T) {
+	expected := map[string]bool{
+		"/healthz":                     true,
+		"/readyz/" + unknownValue:      true,
+		"/metrics?format=text":         true,
+		"/apis":                        true,
+		"/":                            true,
+		"/apis/config.openshift.io/v1": false,
+		"/apis/" + unknownValue + "/v1/clusteroperators": false,
+		unknownValue + "/apis/config.openshift.io/v1":    false,
+		unknownValue: false,
+	}
+	for p, e := range expected {
+		if actual := isNonAPIPath(p); actual != e {
+			t.Errorf("%q: expected %v, got %v", p, e, actual)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package apiusage
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+)
+
+// GroundTruth maps test names to API Groups the tests are known to use (e.g. based on manual inspection).
+// The core API Group can be listed either as "core" or as empty string.
+type GroundTruth map[string][]string
+
+// LoadGroundTruth reads GroundTruth from YAML file in form of:
+//
+//	"[sig-foo] test name": [config.openshift.io, route.openshift.io]
+func LoadGroundTruth(path string) (GroundTruth, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ground truth file: %w", err)
+	}
+	gt := GroundTruth{}
+	if err := yaml.Unmarshal(data, &gt); err != nil {
+		return nil, fmt.Errorf("failed to parse ground truth file %s: %w", path, err)
+	}
+	return gt, nil
+}
+
+// Score summarizes how well API Groups reported by the analyzer match the ground truth for a set of tests.
+type Score struct {
+	// Tests is a number of tests present in the ground truth.
+	Tests int `json:"tests"`
+	// FullyResolved is a number of tests for which detected API Groups are exactly the expected ones.
+	FullyResolved int `json:"fullyResolved"`
+	// PartiallyResolved is a number of tests for which only some expected API Groups were detected,
+	// or unexpected ones were detected as well.
+	PartiallyResolved int `json:"partiallyResolved"`
+	// Unresolved is a number of tests for which none of the expected API Groups was detected.
+	Unresolved int `json:"unresolved"`
+
+	TruePositives  int `json:"truePositives"`
+	FalsePositives int `json:"falsePositives"`
+	FalseNegatives int `json:"falseNegatives"`
+
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+}
+
+// PackageScore is a Score of tests of a single package.
+type PackageScore struct {
+	Package string `json:"package"`
+	Score
+}
+
+// Scorecard compares Report with GroundTruth.
+type Scorecard struct {
+	Overall  Score          `json:"overall"`
+	Packages []PackageScore `json:"packages"`
+	// Missing lists tests present in the ground truth, but not found by the analyzer.
+	// They are counted as unresolved.
+	Missing []string `json:"missing,omitempty"`
+}
+
+// NewScorecard computes the Scorecard of the report against the ground truth.
+// Only tests present in the ground truth are taken into account.
+func NewScorecard(r *Report, gt GroundTruth) *Scorecard {
+	sc := &Scorecard{Packages: []PackageScore{}}
+	byPkg := map[string]*Score{}
+	found := map[string]bool{}
+
+	for _, t := range r.Tests {
+		expected, ok := gt[t.Name]
+		if !ok {
+			continue
+		}
+		found[t.Name] = true
+		if _, ok := byPkg[t.Package]; !ok {
+			byPkg[t.Package] = &Score{}
+		}
+		byPkg[t.Package].add(normalizeGroups(expected), t.APIGroups)
+		sc.Overall.add(normalizeGroups(expected), t.APIGroups)
+	}
+
+	for name, expected := range gt {
+		if !found[name] {
+			sc.Missing = append(sc.Missing, name)
+			sc.Overall.add(normalizeGroups(expected), nil)
+		}
+	}
+	sort.Strings(sc.Missing)
+
+	for pkg, s := range byPkg {
+		s.computeRatios()
+		sc.Packages = append(sc.Packages, PackageScore{Package: pkg, Score: *s})
+	}
+	sort.Slice(sc.Packages, func(i, j int) bool { return sc.Packages[i].Package < sc.Packages[j].Package })
+	sc.Overall.computeRatios()
+
+	return sc
+}
+
+// normalizeGroups replaces "core" with the core API Group's empty name, as it's stored in the report
+func normalizeGroups(groups []string) []string {
+	res := []string{}
+	for _, g := range groups {
+		if g == coreGroupName {
+			g = ""
+		}
+		res = append(res, g)
+	}
+	return uniqueSorted(res)
+}
+
+func (s *Score) add(expected, actual []string) {
+	s.Tests++
+
+	actualSet := map[string]bool{}
+	for _, g := range actual {
+		actualSet[g] = true
+	}
+	tp := 0
+	for _, g := range expected {
+		if actualSet[g] {
+			tp++
+		}
+	}
+	fn := len(expected) - tp
+	fp := len(actual) - tp
+
+	s.TruePositives += tp
+	s.FalseNegatives += fn
+	s.FalsePositives += fp
+
+	switch {
+	case fn == 0 && fp == 0:
+		s.FullyResolved++
+	case tp == 0 && len(expected) != 0:
+		s.Unresolved++
+	default:
+		s.PartiallyResolved++
+	}
+}
+
+func (s *Score) computeRatios() {
+	if s.TruePositives+s.FalsePositives != 0 {
+		s.Precision = float64(s.TruePositives) / float64(s.TruePositives+s.FalsePositives)
+	}
+	if s.TruePositives+s.FalseNegatives != 0 {
+		s.Recall = float64(s.TruePositives) / float64(s.TruePositives+s.FalseNegatives)
+	}
+}
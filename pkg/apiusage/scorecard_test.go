@@ -0,0 +1,48 @@
+package apiusage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewScorecard(t *testing.T) {
+	report := &Report{
+		Tests: []TestUsage{
+			{Name: "fully", Package: "a", APIGroups: []string{"x.openshift.io", "y.openshift.io"}},
+			{Name: "partially", Package: "a", APIGroups: []string{"x.openshift.io", "z.openshift.io"}},
+			{Name: "unresolved", Package: "b", APIGroups: []string{}},
+			{Name: "not in ground truth", Package: "b", APIGroups: []string{"x.openshift.io"}},
+			{Name: "core", Package: "c", APIGroups: []string{"", "x.openshift.io"}},
+		},
+	}
+	gt := GroundTruth{
+		"fully":      {"y.openshift.io", "x.openshift.io"},
+		"partially":  {"x.openshift.io", "y.openshift.io"},
+		"unresolved": {"x.openshift.io"},
+		"missing":    {"x.openshift.io"},
+		"core":       {"core", "x.openshift.io"},
+	}
+
+	sc := NewScorecard(report, gt)
+
+	expectedOverall := Score{
+		Tests:             5,
+		FullyResolved:     2,
+		PartiallyResolved: 1,
+		Unresolved:        2,
+		TruePositives:     5,
+		FalsePositives:    1,
+		FalseNegatives:    3,
+		Precision:         5.0 / 6,
+		Recall:            5.0 / 8,
+	}
+	if !reflect.DeepEqual(sc.Overall, expectedOverall) {
+		t.Errorf("expected overall score %+v, got %+v", expectedOverall, sc.Overall)
+	}
+	if len(sc.Packages) != 3 || sc.Packages[0].Package != "a" || sc.Packages[0].FullyResolved != 1 || sc.Packages[1].Unresolved != 1 || sc.Packages[2].FullyResolved != 1 {
+		t.Errorf("unexpected package scores: %+v", sc.Packages)
+	}
+	if !reflect.DeepEqual(sc.Missing, []string{"missing"}) {
+		t.Errorf("expected missing tests [missing], got %v", sc.Missing)
+	}
+}
@@ -0,0 +1,57 @@
+package apiusage
+
+import (
+	"runtime"
+	"time"
+)
+
+// Stats contains measurements of the analysis, see Config.Stats.
+type Stats struct {
+	// Packages is a number of analyzed packages.
+	Packages int `json:"packages"`
+	// SourcePackages is a number of packages parsed and type-checked from source,
+	// including packages loaded on demand. Other dependencies are type-checked from export data.
+	SourcePackages int `json:"sourcePackages"`
+	// OnDemandPackages is a number of dependencies loaded from source because their functions had to be analyzed.
+	OnDemandPackages int `json:"onDemandPackages"`
+
+	LoadTime      time.Duration `json:"loadTime"`
+	AnalysisTime  time.Duration `json:"analysisTime"`
+	CallGraphTime time.Duration `json:"callGraphTime"`
+	TotalTime     time.Duration `json:"totalTime"`
+
+	// PeakMemory is a maximum resident set size of the process in bytes (0 if not supported by the platform).
+	PeakMemory uint64 `json:"peakMemory"`
+	// TotalAlloc is a cumulative number of bytes allocated on the heap.
+	TotalAlloc uint64 `json:"totalAlloc"`
+}
+
+// stopwatch measures consecutive phases of the analysis
+type stopwatch struct {
+	start time.Time
+	last  time.Time
+}
+
+func newStopwatch() *stopwatch {
+	now := time.Now()
+	return &stopwatch{start: now, last: now}
+}
+
+// lap returns time elapsed since the previous lap
+func (s *stopwatch) lap() time.Duration {
+	now := time.Now()
+	d := now.Sub(s.last)
+	s.last = now
+	return d
+}
+
+func (s *stopwatch) total() time.Duration {
+	return time.Since(s.start)
+}
+
+func (s *Stats) measureMemory() {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	s.TotalAlloc = ms.TotalAlloc
+	s.PeakMemory = peakRSS()
+}
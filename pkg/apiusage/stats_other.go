@@ -0,0 +1,7 @@
+//go:build !unix
+
+package apiusage
+
+func peakRSS() uint64 {
+	return 0
+}
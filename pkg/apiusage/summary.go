@@ -0,0 +1,54 @@
+package apiusage
+
+import (
+	"go/ast"
+	"sync"
+)
+
+// funcSummary is a result of analysis of a function returning GVRs
+type funcSummary struct {
+	groups []string
+	// may is true if more than one definition of some variable reaches the returned value
+	may bool
+	// steps are resolution steps taken when analyzing the function, replayed on cache hit
+	steps []ResolutionStep
+}
+
+// funcSummaryKey identifies a result of a function
+type funcSummaryKey struct {
+	// fun is *ast.FuncDecl or *ast.FuncLit
+	fun    ast.Node
+	result int
+	// typeArgs are type arguments of generic function (see investigator.typeArgsKey)
+	typeArgs string
+}
+
+// summaryCache stores funcSummary of each analyzed function. It's safe for concurrent use,
+// and nil summaryCache is valid (caches nothing).
+type summaryCache struct {
+	mu sync.RWMutex
+	m  map[funcSummaryKey]funcSummary
+}
+
+func newSummaryCache() *summaryCache {
+	return &summaryCache{m: map[funcSummaryKey]funcSummary{}}
+}
+
+func (c *summaryCache) get(key funcSummaryKey) (funcSummary, bool) {
+	if c == nil {
+		return funcSummary{}, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	s, ok := c.m[key]
+	return s, ok
+}
+
+func (c *summaryCache) put(key funcSummaryKey, s funcSummary) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.m[key] = s
+}
@@ -0,0 +1,173 @@
+package apiusage
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+)
+
+// typedVerbs maps methods of typed clients which don't have the name of the verb to the verb and subresource they use,
+// and resource if it differs from the client's one. Other methods (Get, List, Create, DeleteCollection, ...) are lowercased.
+var typedVerbs = map[string]struct{ verb, resource, subresource string }{
+	"Apply":                     {verb: "patch"},
+	"ApplyStatus":               {verb: "patch", subresource: "status"},
+	"UpdateStatus":              {verb: "update", subresource: "status"},
+	"ApplyScale":                {verb: "patch", subresource: "scale"},
+	"GetScale":                  {verb: "get", subresource: "scale"},
+	"UpdateScale":               {verb: "update", subresource: "scale"},
+	"Bind":                      {verb: "create", subresource: "binding"},
+	"Evict":                     {verb: "create", resource: "pods", subresource: "eviction"},
+	"EvictV1":                   {verb: "create", resource: "pods", subresource: "eviction"},
+	"EvictV1beta1":              {verb: "create", resource: "pods", subresource: "eviction"},
+	"GetLogs":                   {verb: "get", subresource: "log"},
+	"ProxyGet":                  {verb: "get", subresource: "proxy"},
+	"UpdateEphemeralContainers": {verb: "update", subresource: "ephemeralcontainers"},
+	"CreateToken":               {verb: "create", subresource: "token"},
+	"UpdateApproval":            {verb: "update", subresource: "approval"},
+}
+
+// typedResource returns resource accessed by the call of typed client generated by client-gen in clientsets
+// of the callee (PKG[/...]/typed/DIR/VERSION): `CoreV1().Pods(ns).List(...)`, `ConfigV1().ClusterOperators().List(...)`.
+// Calls are recognized by the type of receiver, which has to be a resource interface (PodInterface) returned
+// by a getter (PodsGetter) of the package, so it doesn't matter how the client was obtained:
+// `oc.AdminKubeClient()`, `oc.AdminConfigClient()`, `f.ClientSet`, a variable or a parameter.
+// Group of the resource is given by GroupName constant of returned API package (see packageLoader.groupName),
+// unless it's known (the package is nil then).
+func typedResource(info *types.Info, ce *ast.CallExpr, c *apiCallee) (Resource, *types.Package, bool) {
+	sel, ok := ast.Unparen(ce.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return Resource{}, nil, false
+	}
+	t := info.TypeOf(sel.X)
+	if t == nil {
+		// pkg.F
+		return Resource{}, nil, false
+	}
+	named, ok := deref(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return Resource{}, nil, false
+	}
+	clientset, rest, ok := strings.Cut(named.Obj().Pkg().Path(), "/typed/")
+	if !ok || clientset != c.pkg && !strings.HasPrefix(clientset, c.pkg+"/") {
+		return Resource{}, nil, false
+	}
+	dirs := strings.Split(rest, "/")
+	if len(dirs) != 2 {
+		// e.g. fake clients
+		return Resource{}, nil, false
+	}
+	resource := typedResourceName(named)
+	if resource == "" {
+		// group's client: CoreV1().RESTClient()
+		return Resource{}, nil, false
+	}
+	api := typedAPIPackage(named)
+	if api == nil {
+		return Resource{}, nil, false
+	}
+
+	r := Resource{Version: dirs[1], Resource: resource, Verb: strings.ToLower(sel.Sel.Name)}
+	if v, ok := typedVerbs[sel.Sel.Name]; ok {
+		r.Verb, r.Subresource = v.verb, v.subresource
+		if v.resource != "" {
+			// evictions are subresource of core pods even if created by policy's client:
+			// POST /api/v1/namespaces/NS/pods/NAME/eviction
+			r.Group, r.Version, r.Resource = "", "v1", v.resource
+			api = nil
+		}
+	}
+	return r, api, true
+}
+
+// typedResourceName returns name of the resource of typed client's resource interface, or empty string if it's not
+// resource interface. The name is taken from getter returning the interface: `Pods(namespace string) PodInterface`.
+func typedResourceName(iface *types.Named) string {
+	scope := iface.Obj().Pkg().Scope()
+	for _, name := range scope.Names() {
+		if !strings.HasSuffix(name, "Getter") {
+			continue
+		}
+		getter, ok := scope.Lookup(name).Type().Underlying().(*types.Interface)
+		if !ok {
+			continue
+		}
+		for k := 0; k < getter.NumMethods(); k++ {
+			m := getter.Method(k)
+			results := m.Type().(*types.Signature).Results()
+			if results.Len() == 1 && types.Identical(results.At(0).Type(), iface) {
+				return strings.ToLower(m.Name())
+			}
+		}
+	}
+	return ""
+}
+
+// typedAPIPackage returns package of API types the typed client's resource interface works with,
+// e.g. k8s.io/api/core/v1 for PodInterface (`Get(...) (*v1.Pod, error)`) or github.com/openshift/api/config/v1
+// for ClusterOperatorInterface. Results of Get and Create are checked first, as other methods may use types
+// of other API Groups (GetScale returns autoscaling/v1 Scale).
+func typedAPIPackage(iface *types.Named) *types.Package {
+	it, ok := iface.Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+	methods := []*types.Func{}
+	for _, name := range []string{"Get", "Create"} {
+		for k := 0; k < it.NumMethods(); k++ {
+			if m := it.Method(k); m.Name() == name {
+				methods = append(methods, m)
+			}
+		}
+	}
+	for k := 0; k < it.NumMethods(); k++ {
+		methods = append(methods, it.Method(k))
+	}
+	for _, m := range methods {
+		sig := m.Type().(*types.Signature)
+		for _, vars := range []*types.Tuple{sig.Results(), sig.Params()} {
+			for k := 0; k < vars.Len(); k++ {
+				named, ok := deref(vars.At(k).Type()).(*types.Named)
+				if !ok || named.Obj().Pkg() == nil {
+					continue
+				}
+				p := named.Obj().Pkg().Path()
+				// standard library (context.Context), k8s.io/apimachinery (metav1.GetOptions),
+				// k8s.io/client-go (apply configurations, watch) aren't API types
+				if !strings.Contains(strings.Split(p, "/")[0], ".") || strings.HasPrefix(p, "k8s.io/apimachinery/") ||
+					strings.HasPrefix(p, "k8s.io/client-go/") || named.Obj().Pkg() == iface.Obj().Pkg() {
+					continue
+				}
+				return named.Obj().Pkg()
+			}
+		}
+	}
+	return nil
+}
+
+// groupNameConst returns value of GroupName constant declared by API package: const GroupName = "apps"
+func groupNameConst(api *types.Package) (string, bool) {
+	c, ok := api.Scope().Lookup("GroupName").(*types.Const)
+	if !ok || c.Val().Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(c.Val()), true
+}
+
+// typedCall returns API Group of the call of typed client, and records its resource
+func (i *investigator) typedCall(call *ast.CallExpr, callee *apiCallee) []string {
+	i.explain(call)
+	r, api, ok := typedResource(i.pkg.TypesInfo, call, callee)
+	assert(ok)
+	if api != nil {
+		group, err := i.loader.groupName(i.pkg, api)
+		if err != nil {
+			panic(loadError{err})
+		}
+		r.Group = group
+	}
+	if i.resources != nil {
+		*i.resources = append(*i.resources, r)
+	}
+	return []string{r.Group}
+}
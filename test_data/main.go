@@ -2,7 +2,11 @@ package main
 
 import (
 	// make sure test packages are buildable
+	_ "github.com/pmtk/openshift-tests-api-usage/test_data/test/extended/cli"
+	_ "github.com/pmtk/openshift-tests-api-usage/test_data/test/extended/client_go"
 	_ "github.com/pmtk/openshift-tests-api-usage/test_data/test/extended/dynamic_client_go"
+	_ "github.com/pmtk/openshift-tests-api-usage/test_data/test/extended/kubernetes_client_go"
+	_ "github.com/pmtk/openshift-tests-api-usage/test_data/test/extended/rest_paths"
 )
 
 func main() {}
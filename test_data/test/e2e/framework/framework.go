@@ -0,0 +1,15 @@
+// Package framework mimics k8s.io/kubernetes/test/e2e/framework with just enough of Framework for fixtures.
+package framework
+
+import (
+	"k8s.io/client-go/kubernetes"
+)
+
+// Framework holds clients of the test
+type Framework struct {
+	ClientSet kubernetes.Interface
+}
+
+func NewDefaultFramework(baseName string) *Framework {
+	return &Framework{ClientSet: kubernetes.NewForConfigOrDie(nil)}
+}
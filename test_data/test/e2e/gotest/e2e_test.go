@@ -0,0 +1,60 @@
+// mimics e2e tests of operators (e.g. cluster-*-operator/test/e2e) which use plain `go test`.
+// Parent tests (TestSubtests, TestSubtests/parent, ...) are expected to use API Groups of all their subtests.
+
+package gotest
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+func TestSubtests(t *testing.T) {
+	dynamicClient := dynamic.NewForConfigOrDie(nil)
+
+	t.Run("constant name [apigroup:a8c1.openshift.io]", func(t *testing.T) {
+		_ = dynamicClient.Resource(schema.GroupVersionResource{Group: "a8c1.openshift.io", Version: "v1", Resource: "testdata"})
+	})
+
+	t.Run("parent", func(t *testing.T) {
+		t.Run("nested [apigroup:6b3e.openshift.io]", func(t *testing.T) {
+			gvr := schema.GroupVersionResource{Group: "6b3e.openshift.io", Version: "v1", Resource: "testdata"}
+			_ = dynamicClient.Resource(gvr)
+		})
+	})
+}
+
+func TestTableDriven(t *testing.T) {
+	dynamicClient := dynamic.NewForConfigOrDie(nil)
+
+	gvr := schema.GroupVersionResource{Group: "0c7d.openshift.io", Version: "v1", Resource: "testdata"}
+	testCases := []struct {
+		name    string
+		objName string
+	}{
+		{name: "first [apigroup:0c7d.openshift.io]", objName: "test-1"},
+		{name: "second [apigroup:0c7d.openshift.io]", objName: "test-2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _ = dynamicClient.Resource(gvr).Get(context.TODO(), tc.objName, metav1.GetOptions{})
+		})
+	}
+}
+
+func TestMapDriven(t *testing.T) {
+	dynamicClient := dynamic.NewForConfigOrDie(nil)
+
+	for name, objName := range map[string]string{
+		"first [apigroup:77aa.openshift.io]":  "test-1",
+		"second [apigroup:77aa.openshift.io]": "test-2",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _ = dynamicClient.Resource(schema.GroupVersionResource{Group: "77aa.openshift.io", Version: "v1", Resource: "testdata"}).Get(context.TODO(), objName, metav1.GetOptions{})
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	g "github.com/onsi/ginkgo/v2"
+
+	exutil "github.com/pmtk/openshift-tests-api-usage/test_data/test/extended/util"
+)
+
+var _ = g.Describe("oc get --raw", func() {
+	oc := exutil.NewCLI("raw")
+
+	g.It("raw path [apigroup:f2a1.openshift.io]", func() {
+		_, _ = oc.AsAdmin().Run("get").Args("--raw", "/apis/f2a1.openshift.io/v1/clusteroperators").Output()
+	})
+
+	g.It("raw flag with value [apigroup:f2b1.openshift.io]", func() {
+		_, _ = oc.Run("get").Args("--raw=/apis/f2b1.openshift.io/v1/namespaces/" + oc.Namespace() + "/routes").Output()
+	})
+
+	g.It("raw flag passed to Run [apigroup:f2c1.openshift.io]", func() {
+		p := "/apis/f2c1.openshift.io/v1/images"
+		_, _ = oc.Run("get", "--raw", p).Output()
+	})
+
+	g.It("oc without raw flag", func() {
+		_, _ = oc.Run("get").Args("clusteroperators.f2d1.openshift.io").Output()
+	})
+
+	g.It("raw metrics", func() {
+		_, _ = oc.Run("get").Args("--raw", "/metrics").Output()
+	})
+})
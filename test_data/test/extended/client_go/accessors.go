@@ -0,0 +1,54 @@
+package client_go
+
+import (
+	"context"
+
+	g "github.com/onsi/ginkgo/v2"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configv1client "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+	imageregistryclient "github.com/openshift/client-go/imageregistry/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	exutil "github.com/pmtk/openshift-tests-api-usage/test_data/test/extended/util"
+)
+
+// clusterVersion gets the cluster version using group's client passed as a parameter
+func clusterVersion(ctx context.Context, client configv1client.ConfigV1Interface) (*configv1.ClusterVersion, error) {
+	return client.ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+}
+
+var _ = g.Describe("exutil.CLI client accessors", func() {
+	oc := exutil.NewCLI("accessors")
+	ctx := context.Background()
+
+	g.It("cluster operators of admin config client [apigroup:config.openshift.io]", func() {
+		_, _ = oc.AdminConfigClient().ConfigV1().ClusterOperators().List(ctx, metav1.ListOptions{})
+	})
+
+	g.It("clientset stored in a variable [apigroup:route.openshift.io]", func() {
+		routeClient := oc.AdminRouteClient()
+		routes := routeClient.RouteV1().Routes(oc.Namespace())
+		_ = routes.Delete(ctx, "name", metav1.DeleteOptions{})
+	})
+
+	g.It("image streams and operator status [apigroup:image.openshift.io][apigroup:operator.openshift.io]", func() {
+		_, _ = oc.AdminImageClient().ImageV1().ImageStreams("openshift").Get(ctx, "cli", metav1.GetOptions{})
+		_, _ = oc.AdminOperatorClient().OperatorV1().KubeAPIServers().UpdateStatus(ctx, nil, metav1.UpdateOptions{})
+	})
+
+	g.It("group client passed to helper [apigroup:config.openshift.io]", func() {
+		_, _ = clusterVersion(ctx, oc.AdminConfigClient().ConfigV1())
+	})
+
+	g.It("admin dynamic client [apigroup:console.openshift.io]", func() {
+		gvr := schema.GroupVersionResource{Group: "console.openshift.io", Version: "v1", Resource: "consoleplugins"}
+		_, _ = oc.AdminDynamicClient().Resource(gvr).List(ctx, metav1.ListOptions{})
+	})
+
+	g.It("clientset whose API Group isn't named after its dir [apigroup:imageregistry.operator.openshift.io]", func() {
+		imageRegistryClient := imageregistryclient.NewForConfigOrDie(nil)
+		_, _ = imageRegistryClient.ImageregistryV1().Configs().Get(ctx, "cluster", metav1.GetOptions{})
+	})
+})
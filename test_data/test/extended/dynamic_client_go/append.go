@@ -0,0 +1,104 @@
+package dynamic_client_go
+
+import (
+	"os"
+
+	g "github.com/onsi/ginkgo/v2"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// registeredGVRs is filled by init and helper functions
+var registeredGVRs []schema.GroupVersionResource
+
+func init() {
+	registeredGVRs = append(registeredGVRs, schema.GroupVersionResource{Group: "b5f1.openshift.io", Version: "v1", Resource: "testdata"})
+}
+
+func registerGVR() {
+	registeredGVRs = append(registeredGVRs, localGVR("b5f2.openshift.io", "v1", "testdata"))
+}
+
+var _ = g.Describe("GVRs inserted into collections are followed", func() {
+	g.It("slice built with append [apigroup:b5a1.openshift.io][apigroup:b5a2.openshift.io]", func() {
+		gvrs := []schema.GroupVersionResource{}
+		gvrs = append(gvrs, schema.GroupVersionResource{Group: "b5a1.openshift.io", Version: "v1", Resource: "testdata"})
+		gvrs = append(gvrs, localGVR("b5a2.openshift.io", "v1", "testdata"))
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for _, gvr := range gvrs {
+			_ = dynamicClient.Resource(gvr)
+		}
+	})
+
+	g.It("slice declared without value and appended to in a branch [apigroup:b5b1.openshift.io]", func() {
+		var gvrs []schema.GroupVersionResource
+		if os.Getenv("B5B") != "" {
+			gvrs = append(gvrs, schema.GroupVersionResource{Group: "b5b1.openshift.io", Version: "v1", Resource: "testdata"})
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for _, gvr := range gvrs {
+			_ = dynamicClient.Resource(gvr)
+		}
+	})
+
+	g.It("keys inserted into map [apigroup:b5c1.openshift.io][apigroup:b5c2.openshift.io]", func() {
+		m := map[schema.GroupVersionResource]bool{{Group: "b5c1.openshift.io"}: true}
+		m[schema.GroupVersionResource{Group: "b5c2.openshift.io", Version: "v1", Resource: "testdata"}] = true
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for gvr := range m {
+			_ = dynamicClient.Resource(gvr)
+		}
+	})
+
+	g.It("values inserted into map made with make [apigroup:b5d1.openshift.io]", func() {
+		m := make(map[string]schema.GroupVersionResource)
+		m["b5d1"] = schema.GroupVersionResource{Group: "b5d1.openshift.io", Version: "v1", Resource: "testdata"}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for _, gvr := range m {
+			_ = dynamicClient.Resource(gvr)
+		}
+	})
+
+	g.It("slice filled by copy [apigroup:b5e1.openshift.io]", func() {
+		gvrs := make([]schema.GroupVersionResource, 1)
+		copy(gvrs, []schema.GroupVersionResource{{Group: "b5e1.openshift.io", Version: "v1", Resource: "testdata"}})
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for _, gvr := range gvrs {
+			_ = dynamicClient.Resource(gvr)
+		}
+	})
+
+	g.It("package-level slice appended to by init and helpers [apigroup:b5f1.openshift.io][apigroup:b5f2.openshift.io]", func() {
+		registerGVR()
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for _, gvr := range registeredGVRs {
+			_ = dynamicClient.Resource(gvr)
+		}
+	})
+
+	g.It("slice built with append by helper function [apigroup:b5g1.openshift.io][apigroup:b5g2.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for _, gvr := range appendedGVRs() {
+			_ = dynamicClient.Resource(gvr)
+		}
+	})
+
+	g.It("slices joined with append [apigroup:b5h1.openshift.io][apigroup:b5h2.openshift.io]", func() {
+		first := []schema.GroupVersionResource{{Group: "b5h1.openshift.io", Version: "v1", Resource: "testdata"}}
+		second := []schema.GroupVersionResource{{Group: "b5h2.openshift.io", Version: "v1", Resource: "testdata"}}
+		all := append(first, second...)
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for _, gvr := range all {
+			_ = dynamicClient.Resource(gvr)
+		}
+	})
+})
+
+func appendedGVRs() []schema.GroupVersionResource {
+	res := []schema.GroupVersionResource{{Group: "b5g1.openshift.io", Version: "v1", Resource: "testdata"}}
+	for _, r := range []string{"a", "b"} {
+		res = append(res, schema.GroupVersionResource{Group: "b5g2.openshift.io", Version: "v1", Resource: r})
+	}
+	return res
+}
@@ -0,0 +1,73 @@
+package dynamic_client_go
+
+import (
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+)
+
+var _ = g.Describe("GVRs are followed through closures and function literals", func() {
+	capturedGVR := schema.GroupVersionResource{Group: "c9e1.openshift.io", Version: "v1", Resource: "testdata"}
+
+	g.It("immediately invoked function literal [apigroup:c9a1.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(func() schema.GroupVersionResource {
+			return schema.GroupVersionResource{Group: "c9a1.openshift.io", Version: "v1", Resource: "testdata"}
+		}())
+	})
+
+	g.It("function literal stored in variable [apigroup:c9b1.openshift.io]", func() {
+		mk := func() schema.GroupVersionResource {
+			return localGVR("c9b1.openshift.io", "v1", "testdata")
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(mk())
+	})
+
+	g.It("GVR helper function literal [apigroup:c9c1.openshift.io]", func() {
+		mk := func(g, v, r string) schema.GroupVersionResource {
+			return schema.GroupVersionResource{Group: g, Version: v, Resource: r}
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(mk("c9c1.openshift.io", "v1", "testdata"))
+	})
+
+	g.It("function stored in variable [apigroup:c9d1.openshift.io]", func() {
+		var mk func() schema.GroupVersionResource = closureGVR
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(mk())
+	})
+
+	g.It("variable captured from Describe [apigroup:c9e1.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(capturedGVR)
+	})
+
+	g.It("variable captured by goroutine [apigroup:c9f1.openshift.io]", func() {
+		gvr := schema.GroupVersionResource{Group: "c9f1.openshift.io", Version: "v1", Resource: "testdata"}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = dynamicClient.Resource(gvr)
+		}()
+		<-done
+	})
+
+	g.It("variable captured by wait.Poll condition [apigroup:c9g1.openshift.io]", func() {
+		gvr := schema.GroupVersionResource{Group: "c9g1.openshift.io", Version: "v1", Resource: "testdata"}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = wait.Poll(time.Second, time.Minute, func() (bool, error) {
+			_ = dynamicClient.Resource(gvr)
+			return true, nil
+		})
+	})
+})
+
+func closureGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: "c9d1.openshift.io", Version: "v1", Resource: "testdata"}
+}
@@ -0,0 +1,37 @@
+package dynamic_client_go
+
+import (
+	g "github.com/onsi/ginkgo/v2"
+
+	"github.com/pmtk/openshift-tests-api-usage/test_data/test/extended/dynamic_client_go/other_pkg"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var _ = g.Describe("GVR or its parts are declared in another file or package", func() {
+	g.It("package-level gvr var is declared in another file [apigroup:c7f1.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(crossFileGVR)
+	})
+
+	g.It("package-level group const is declared in another file [apigroup:c7f2.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(schema.GroupVersionResource{Group: crossFileGroup, Version: "v1", Resource: "testdata"})
+	})
+
+	g.It("gvr var initialized with helper is declared in another file [apigroup:c7f4.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(crossFileHelperGVR)
+	})
+
+	g.It("gvr var is exported by another package [apigroup:c7f3.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(other_pkg.ExportedGVR)
+	})
+
+	g.It("group const is exported by another package [apigroup:c7f5.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(localGVR(other_pkg.ExportedGroup, "v1", "testdata"))
+	})
+})
@@ -0,0 +1,13 @@
+package dynamic_client_go
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// used by cross_file.go
+
+var crossFileGVR = schema.GroupVersionResource{Group: "c7f1.openshift.io", Version: "v1", Resource: "testdata"}
+
+const crossFileGroup = "c7f2.openshift.io"
+
+var crossFileHelperGVR = localGVR("c7f4.openshift.io", "v1", "testdata")
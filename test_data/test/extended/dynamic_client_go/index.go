@@ -0,0 +1,80 @@
+package dynamic_client_go
+
+import (
+	g "github.com/onsi/ginkgo/v2"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/pmtk/openshift-tests-api-usage/test_data/test/extended/dynamic_client_go/other_pkg"
+)
+
+const routesKey = "routes"
+
+var _ = g.Describe("Elements of GVR collections selected by index or key", func() {
+	g.It("constant index of slice [apigroup:a7a1.openshift.io]", func() {
+		gvrs := []schema.GroupVersionResource{
+			{Group: "a7a1.openshift.io", Version: "v1", Resource: "testdata"},
+			{Group: "a7a2.openshift.io", Version: "v1", Resource: "testdata"},
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvrs[0])
+	})
+
+	g.It("constant key of map [apigroup:a7b1.openshift.io]", func() {
+		gvrByName := map[string]schema.GroupVersionResource{
+			routesKey: {Group: "a7b1.openshift.io", Version: "v1", Resource: "routes"},
+			"builds":  {Group: "a7b2.openshift.io", Version: "v1", Resource: "builds"},
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvrByName["routes"])
+	})
+
+	g.It("non-constant index selects all elements [apigroup:a7c1.openshift.io][apigroup:a7c2.openshift.io]", func() {
+		gvrs := []schema.GroupVersionResource{
+			{Group: "a7c1.openshift.io", Version: "v1", Resource: "testdata"},
+			{Group: "a7c2.openshift.io", Version: "v1", Resource: "testdata"},
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for i := range gvrs {
+			_ = dynamicClient.Resource(gvrs[i])
+		}
+	})
+
+	g.It("array with indexed elements [apigroup:a7d2.openshift.io]", func() {
+		gvrs := [...]schema.GroupVersionResource{
+			2: {Group: "a7d1.openshift.io", Version: "v1", Resource: "testdata"},
+			{Group: "a7d2.openshift.io", Version: "v1", Resource: "testdata"},
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvrs[3])
+	})
+
+	g.It("key of map returned by helper [apigroup:a7e2.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(other_pkg.GVRsByName()["builds"])
+	})
+
+	g.It("index of slice returned by helper with other values [apigroup:a7f2.openshift.io]", func() {
+		_, gvrs := other_pkg.IndexedGVRs()
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvrs[1])
+	})
+
+	g.It("map lookup with comma ok [apigroup:a7g1.openshift.io]", func() {
+		gvrByName := map[string]schema.GroupVersionResource{"routes": {Group: "a7g1.openshift.io", Version: "v1", Resource: "routes"}}
+		gvr, ok := gvrByName["routes"]
+		if !ok {
+			return
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvr)
+	})
+
+	g.It("index past literal elements of slice built with append [apigroup:a7h2.openshift.io]", func() {
+		gvrs := []schema.GroupVersionResource{{Group: "a7h1.openshift.io", Version: "v1", Resource: "testdata"}}
+		gvrs = append(gvrs, schema.GroupVersionResource{Group: "a7h2.openshift.io", Version: "v1", Resource: "testdata"})
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvrs[1])
+	})
+})
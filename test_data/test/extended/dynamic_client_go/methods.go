@@ -0,0 +1,80 @@
+package dynamic_client_go
+
+import (
+	g "github.com/onsi/ginkgo/v2"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+type gvrProvider interface {
+	GVR() schema.GroupVersionResource
+}
+
+type routeProvider struct{}
+
+func (routeProvider) GVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: "e7a1.openshift.io", Version: "v1", Resource: "routes"}
+}
+
+type fieldProvider struct {
+	gvr schema.GroupVersionResource
+}
+
+func (p *fieldProvider) GVR() schema.GroupVersionResource {
+	return p.gvr
+}
+
+type buildProvider struct{}
+
+func (buildProvider) GVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: "e7c1.openshift.io", Version: "v1", Resource: "builds"}
+}
+
+func resourceOf[T gvrProvider](p T) schema.GroupVersionResource {
+	return p.GVR()
+}
+
+type providerHolder[T gvrProvider] struct {
+	p T
+}
+
+func (h providerHolder[T]) GVR() schema.GroupVersionResource {
+	return h.p.GVR()
+}
+
+var _ = g.Describe("GVRs returned by methods and generic functions", func() {
+	g.It("method of concrete type [apigroup:e7a1.openshift.io]", func() {
+		p := routeProvider{}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(p.GVR())
+	})
+
+	g.It("method returning field [apigroup:e7b1.openshift.io]", func() {
+		p := &fieldProvider{gvr: schema.GroupVersionResource{Group: "e7b1.openshift.io", Version: "v1", Resource: "testdata"}}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(p.GVR())
+	})
+
+	g.It("interface method dispatched to all implementations [apigroup:e7a1.openshift.io][apigroup:e7b1.openshift.io][apigroup:e7c1.openshift.io][may]", func() {
+		var p gvrProvider = buildProvider{}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(p.GVR())
+	})
+
+	g.It("generic function with inferred type argument [apigroup:e7c1.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(resourceOf(buildProvider{}))
+	})
+
+	g.It("generic function with explicit type argument [apigroup:e7a1.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(resourceOf[routeProvider](routeProvider{}))
+	})
+
+	g.It("method of generic type [apigroup:e7c1.openshift.io]", func() {
+		h := providerHolder[buildProvider]{}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(h.GVR())
+	})
+})
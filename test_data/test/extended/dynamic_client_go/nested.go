@@ -0,0 +1,131 @@
+package dynamic_client_go
+
+import (
+	g "github.com/onsi/ginkgo/v2"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+type gvrHolder struct {
+	name string
+	gvrs []schema.GroupVersionResource
+}
+
+type gvrList struct {
+	gvrs []schema.GroupVersionResource
+}
+
+var _ = g.Describe("Range over expressions producing GVR collections", func() {
+	g.It("values of map returned by helper [apigroup:f6a1.openshift.io][apigroup:f6a2.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for _, gvr := range gvrsByName() {
+			_ = dynamicClient.Resource(gvr)
+		}
+	})
+
+	g.It("keys of map returned by helper [apigroup:f6b1.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for gvr := range gvrSet() {
+			_ = dynamicClient.Resource(gvr)
+		}
+	})
+
+	g.It("struct field [apigroup:f6c1.openshift.io]", func() {
+		h := gvrHolder{name: "f6c", gvrs: []schema.GroupVersionResource{{Group: "f6c1.openshift.io", Version: "v1", Resource: "testdata"}}}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for _, gvr := range h.gvrs {
+			_ = dynamicClient.Resource(gvr)
+		}
+	})
+
+	g.It("field of test case [apigroup:f6d1.openshift.io][apigroup:f6d2.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for _, tc := range []struct {
+			name string
+			gvr  schema.GroupVersionResource
+		}{
+			{"first", schema.GroupVersionResource{Group: "f6d1.openshift.io", Version: "v1", Resource: "testdata"}},
+			{name: "second", gvr: schema.GroupVersionResource{Group: "f6d2.openshift.io", Version: "v1", Resource: "testdata"}},
+		} {
+			_ = dynamicClient.Resource(tc.gvr)
+		}
+	})
+
+	g.It("slice of slices [apigroup:f6e1.openshift.io][apigroup:f6e2.openshift.io]", func() {
+		nested := [][]schema.GroupVersionResource{
+			{{Group: "f6e1.openshift.io", Version: "v1", Resource: "testdata"}},
+			{{Group: "f6e2.openshift.io", Version: "v1", Resource: "testdata"}},
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for _, gvrs := range nested {
+			for _, gvr := range gvrs {
+				_ = dynamicClient.Resource(gvr)
+			}
+		}
+	})
+
+	g.It("map of slices [apigroup:f6f1.openshift.io][apigroup:f6f2.openshift.io]", func() {
+		byGroup := map[string][]schema.GroupVersionResource{
+			"f6f1": {{Group: "f6f1.openshift.io", Version: "v1", Resource: "testdata"}},
+			"f6f2": {{Group: "f6f2.openshift.io", Version: "v1", Resource: "testdata"}},
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for _, gvrs := range byGroup {
+			for _, gvr := range gvrs {
+				_ = dynamicClient.Resource(gvr)
+			}
+		}
+	})
+
+	g.It("keys of nested maps [apigroup:f6g1.openshift.io]", func() {
+		sets := map[string]map[schema.GroupVersionResource]bool{
+			"f6g": {{Group: "f6g1.openshift.io", Version: "v1", Resource: "testdata"}: true},
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for _, set := range sets {
+			for gvr := range set {
+				_ = dynamicClient.Resource(gvr)
+			}
+		}
+	})
+
+	g.It("Group field of another gvr [apigroup:f6h1.openshift.io]", func() {
+		gvr := schema.GroupVersionResource{Group: "f6h1.openshift.io", Version: "v1", Resource: "testdata"}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(schema.GroupVersionResource{Group: gvr.Group, Version: "v2", Resource: gvr.Resource})
+	})
+
+	g.It("struct field appended to [apigroup:f6i1.openshift.io]", func() {
+		var l gvrList
+		l.gvrs = append(l.gvrs, schema.GroupVersionResource{Group: "f6i1.openshift.io", Version: "v1", Resource: "testdata"})
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for _, gvr := range l.gvrs {
+			_ = dynamicClient.Resource(gvr)
+		}
+	})
+
+	g.It("element of slice of slices [apigroup:f6j2.openshift.io]", func() {
+		nested := [][]schema.GroupVersionResource{
+			{{Group: "f6j1.openshift.io", Version: "v1", Resource: "testdata"}},
+			{{Group: "f6j2.openshift.io", Version: "v1", Resource: "testdata"}},
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for _, gvr := range nested[1] {
+			_ = dynamicClient.Resource(gvr)
+		}
+	})
+})
+
+func gvrsByName() map[string]schema.GroupVersionResource {
+	return map[string]schema.GroupVersionResource{
+		"f6a1": {Group: "f6a1.openshift.io", Version: "v1", Resource: "testdata"},
+		"f6a2": {Group: "f6a2.openshift.io", Version: "v1", Resource: "testdata"},
+	}
+}
+
+func gvrSet() map[schema.GroupVersionResource]bool {
+	return map[schema.GroupVersionResource]bool{
+		{Group: "f6b1.openshift.io", Version: "v1", Resource: "testdata"}: true,
+	}
+}
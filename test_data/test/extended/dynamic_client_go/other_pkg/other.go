@@ -57,3 +57,22 @@ func GVR(g, v, r string) schema.GroupVersionResource {
 //}
 
 // TODO: Function returning struct containing GVR
+
+var ExportedGVR = schema.GroupVersionResource{Group: "c7f3.openshift.io", Version: "v1", Resource: "testdata"}
+
+const ExportedGroup = "c7f5.openshift.io"
+
+func GVRsByName() map[string]schema.GroupVersionResource {
+	return map[string]schema.GroupVersionResource{
+		"routes": {Group: "a7e1.openshift.io", Version: "v1", Resource: "routes"},
+		"builds": {Group: "a7e2.openshift.io", Version: "v1", Resource: "builds"},
+	}
+}
+
+func IndexedGVRs() (int, []schema.GroupVersionResource) {
+	gvrs := []schema.GroupVersionResource{
+		{Group: "a7f1.openshift.io", Version: "v1", Resource: "testdata"},
+		{Group: "a7f2.openshift.io", Version: "v1", Resource: "testdata"},
+	}
+	return len(gvrs), gvrs
+}
@@ -0,0 +1,44 @@
+package dynamic_client_go
+
+import (
+	"context"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+)
+
+// waitForA2b1 polls using a closure, which is only called by wait.Poll (outside of the module)
+func waitForA2b1(client dynamic.Interface) error {
+	return wait.Poll(time.Second, time.Minute, func() (bool, error) {
+		gvr := schema.GroupVersionResource{Group: "a2b1.openshift.io", Version: "v1", Resource: "testdata"}
+		_, err := client.Resource(gvr).List(context.Background(), metav1.ListOptions{})
+		return err == nil, nil
+	})
+}
+
+// waitForA2c1 polls immediately using a closure nested in another closure
+func waitForA2c1(client dynamic.Interface) error {
+	return wait.PollImmediate(time.Second, time.Minute, func() (bool, error) {
+		list := func() error {
+			gvr := schema.GroupVersionResource{Group: "a2c1.openshift.io", Version: "v1", Resource: "testdata"}
+			_, err := client.Resource(gvr).List(context.Background(), metav1.ListOptions{})
+			return err
+		}
+		return list() == nil, nil
+	})
+}
+
+var _ = g.Describe("API calls in closures of helpers", func() {
+	g.It("helper polling with closure [apigroup:a2b1.openshift.io]", func() {
+		_ = waitForA2b1(dynamic.NewForConfigOrDie(nil))
+	})
+
+	g.It("helper polling immediately with nested closure [apigroup:a2c1.openshift.io]", func() {
+		_ = waitForA2c1(dynamic.NewForConfigOrDie(nil))
+	})
+})
@@ -0,0 +1,96 @@
+package dynamic_client_go
+
+import (
+	"os"
+
+	g "github.com/onsi/ginkgo/v2"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var _ = g.Describe("All definitions of the variable reaching Resource() are followed", func() {
+	g.It("later reassignment replaces the gvr [apigroup:d4a2.openshift.io]", func() {
+		gvr := schema.GroupVersionResource{Group: "d4a1.openshift.io", Version: "v1", Resource: "testdata"}
+		gvr = schema.GroupVersionResource{Group: "d4a2.openshift.io", Version: "v1", Resource: "testdata"}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvr)
+	})
+
+	g.It("reassignment in if branch [apigroup:d4b1.openshift.io][apigroup:d4b2.openshift.io][may]", func() {
+		gvr := schema.GroupVersionResource{Group: "d4b1.openshift.io", Version: "v1", Resource: "testdata"}
+		if os.Getenv("D4B") != "" {
+			gvr = localGVR("d4b2.openshift.io", "v1", "testdata")
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvr)
+	})
+
+	g.It("assignments in switch cases [apigroup:d4c1.openshift.io][apigroup:d4c2.openshift.io][may]", func() {
+		var gvr schema.GroupVersionResource
+		switch os.Getenv("D4C") {
+		case "1":
+			gvr = schema.GroupVersionResource{Group: "d4c1.openshift.io", Version: "v1", Resource: "testdata"}
+		default:
+			gvr = schema.GroupVersionResource{Group: "d4c2.openshift.io", Version: "v1", Resource: "testdata"}
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvr)
+	})
+
+	g.It("write of Group field [apigroup:d4d2.openshift.io]", func() {
+		gvr := schema.GroupVersionResource{Group: "d4d1.openshift.io", Version: "v1", Resource: "testdata"}
+		gvr.Version = "v2"
+		gvr.Group = "d4d2.openshift.io"
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvr)
+	})
+
+	g.It("reassignment after use in a loop [apigroup:d4e1.openshift.io][apigroup:d4e2.openshift.io][may]", func() {
+		gvr := schema.GroupVersionResource{Group: "d4e1.openshift.io", Version: "v1", Resource: "testdata"}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for range []int{1, 2} {
+			_ = dynamicClient.Resource(gvr)
+			gvr = schema.GroupVersionResource{Group: "d4e2.openshift.io", Version: "v1", Resource: "testdata"}
+		}
+	})
+
+	g.It("reassignment after use doesn't reach it [apigroup:d4f1.openshift.io]", func() {
+		gvr := schema.GroupVersionResource{Group: "d4f1.openshift.io", Version: "v1", Resource: "testdata"}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvr)
+		gvr = schema.GroupVersionResource{Group: "d4f2.openshift.io", Version: "v1", Resource: "testdata"}
+		_ = gvr
+	})
+
+	g.It("group extended in a loop reaches itself [apigroup:d4h1.openshift.io]", func() {
+		group := "d4h1.openshift.io"
+		for _, s := range []string{"", "-canary"} {
+			group = group + s
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(schema.GroupVersionResource{Group: group, Version: "v1", Resource: "testdata"})
+	})
+
+	g.It("gvr copied around a loop reaches itself [apigroup:d4i1.openshift.io]", func() {
+		gvr := schema.GroupVersionResource{Group: "d4i1.openshift.io", Version: "v1", Resource: "testdata"}
+		for k := 0; k < 3; k++ {
+			prev := gvr
+			gvr = prev
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvr)
+	})
+})
+
+var _ = g.Describe("Variable assigned by BeforeEach", func() {
+	var gvr schema.GroupVersionResource
+	g.BeforeEach(func() {
+		gvr = schema.GroupVersionResource{Group: "d4g1.openshift.io", Version: "v1", Resource: "testdata"}
+	})
+
+	g.It("is followed into the closure [apigroup:d4g1.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvr)
+	})
+})
@@ -0,0 +1,74 @@
+package dynamic_client_go
+
+import (
+	g "github.com/onsi/ginkgo/v2"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/dynamic/dynamiclister"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+)
+
+// metricsClient mimics unrelated client with Resource method, e.g. of Prometheus
+type metricsClient struct{}
+
+func (metricsClient) Resource(name string) string {
+	return name
+}
+
+// embeddingClient gets Resource method from embedded dynamic.Interface
+type embeddingClient struct {
+	dynamic.Interface
+}
+
+// wrappingClient implements dynamic.Interface itself
+type wrappingClient struct {
+	embeddingClient
+}
+
+func (c wrappingClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return c.embeddingClient.Resource(gvr)
+}
+
+var _ = g.Describe("API calls are recognized by receiver's type", func() {
+	g.It("unrelated Resource method is not API call", func() {
+		c := metricsClient{}
+		_ = c.Resource("d3a9.openshift.io")
+	})
+
+	g.It("metadata client [apigroup:d3b1.openshift.io]", func() {
+		metadataClient := metadata.NewForConfigOrDie(nil)
+		_ = metadataClient.Resource(schema.GroupVersionResource{Group: "d3b1.openshift.io", Version: "v1", Resource: "testdata"})
+	})
+
+	g.It("dynamic informer factory [apigroup:d3c1.openshift.io]", func() {
+		factory := dynamicinformer.NewDynamicSharedInformerFactory(dynamic.NewForConfigOrDie(nil), 0)
+		_ = factory.ForResource(schema.GroupVersionResource{Group: "d3c1.openshift.io", Version: "v1", Resource: "testdata"})
+	})
+
+	g.It("filtered dynamic informer [apigroup:d3d1.openshift.io]", func() {
+		gvr := schema.GroupVersionResource{Group: "d3d1.openshift.io", Version: "v1", Resource: "testdata"}
+		_ = dynamicinformer.NewFilteredDynamicInformer(dynamic.NewForConfigOrDie(nil), gvr, "", 0, nil, nil)
+	})
+
+	g.It("dynamic lister [apigroup:d3e1.openshift.io]", func() {
+		_ = dynamiclister.New(nil, schema.GroupVersionResource{Group: "d3e1.openshift.io", Version: "v1", Resource: "testdata"})
+	})
+
+	g.It("metadata informer factory [apigroup:d3f1.openshift.io]", func() {
+		factory := metadatainformer.NewSharedInformerFactory(metadata.NewForConfigOrDie(nil), 0)
+		_ = factory.ForResource(schema.GroupVersionResource{Group: "d3f1.openshift.io", Version: "v1", Resource: "testdata"})
+	})
+
+	g.It("struct embedding dynamic.Interface [apigroup:d3g1.openshift.io]", func() {
+		c := embeddingClient{dynamic.NewForConfigOrDie(nil)}
+		_ = c.Resource(schema.GroupVersionResource{Group: "d3g1.openshift.io", Version: "v1", Resource: "testdata"})
+	})
+
+	g.It("wrapper implementing dynamic.Interface [apigroup:d3h1.openshift.io]", func() {
+		c := wrappingClient{embeddingClient{dynamic.NewForConfigOrDie(nil)}}
+		_ = c.Resource(schema.GroupVersionResource{Group: "d3h1.openshift.io", Version: "v1", Resource: "testdata"})
+	})
+})
@@ -0,0 +1,141 @@
+package dynamic_client_go
+
+import (
+	"errors"
+	"os"
+
+	g "github.com/onsi/ginkgo/v2"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var _ = g.Describe("All return statements of functions are followed", func() {
+	g.It("early returns in switch [apigroup:b8a1.openshift.io][apigroup:b8a2.openshift.io][apigroup:b8a3.openshift.io][may]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvrFor("a"))
+	})
+
+	g.It("early return of zero gvr with error [apigroup:b8b1.openshift.io]", func() {
+		gvr, err := gvrOrErr()
+		if err != nil {
+			return
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvr)
+	})
+
+	g.It("named result [apigroup:b8c1.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(namedGVR())
+	})
+
+	g.It("named result assigned in branch [apigroup:b8d1.openshift.io][apigroup:b8d2.openshift.io][may]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(namedGVRBranches())
+	})
+
+	g.It("named collection result [apigroup:b8e1.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for _, gvr := range namedGVRs() {
+			_ = dynamicClient.Resource(gvr)
+		}
+	})
+
+	g.It("function terminated by panic [apigroup:b8f1.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(mustGVR("b8f1"))
+	})
+
+	g.It("function failing the test [apigroup:b8g1.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvrOrFail("b8g1"))
+	})
+
+	g.It("returns of nested function literals are skipped [apigroup:b8h1.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvrWithClosure())
+	})
+
+	g.It("recursive helper [apigroup:b8i1.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(recursiveGVR(3))
+	})
+
+	g.It("element of collection returned by recursive helper [apigroup:b8j1.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(recursiveGVRs(3)[0])
+	})
+})
+
+func gvrFor(kind string) schema.GroupVersionResource {
+	switch kind {
+	case "a":
+		return schema.GroupVersionResource{Group: "b8a1.openshift.io", Version: "v1", Resource: "testdata"}
+	case "b":
+		return schema.GroupVersionResource{Group: "b8a2.openshift.io", Version: "v1", Resource: "testdata"}
+	}
+	return schema.GroupVersionResource{Group: "b8a3.openshift.io", Version: "v1", Resource: "testdata"}
+}
+
+func gvrOrErr() (schema.GroupVersionResource, error) {
+	if os.Getenv("B8B") != "" {
+		return schema.GroupVersionResource{}, errors.New("no gvr")
+	}
+	return schema.GroupVersionResource{Group: "b8b1.openshift.io", Version: "v1", Resource: "testdata"}, nil
+}
+
+func namedGVR() (gvr schema.GroupVersionResource) {
+	gvr = schema.GroupVersionResource{Group: "b8c1.openshift.io", Version: "v1", Resource: "testdata"}
+	return
+}
+
+func namedGVRBranches() (gvr schema.GroupVersionResource) {
+	gvr = schema.GroupVersionResource{Group: "b8d1.openshift.io", Version: "v1", Resource: "testdata"}
+	if os.Getenv("B8D") != "" {
+		gvr = schema.GroupVersionResource{Group: "b8d2.openshift.io", Version: "v1", Resource: "testdata"}
+	}
+	return
+}
+
+func namedGVRs() (gvrs []schema.GroupVersionResource) {
+	gvrs = append(gvrs, schema.GroupVersionResource{Group: "b8e1.openshift.io", Version: "v1", Resource: "testdata"})
+	return
+}
+
+func mustGVR(name string) schema.GroupVersionResource {
+	if name == "b8f1" {
+		return schema.GroupVersionResource{Group: "b8f1.openshift.io", Version: "v1", Resource: "testdata"}
+	}
+	panic("unknown gvr " + name)
+}
+
+func gvrOrFail(name string) schema.GroupVersionResource {
+	if name != "" {
+		return schema.GroupVersionResource{Group: "b8g1.openshift.io", Version: "v1", Resource: "testdata"}
+	}
+	g.Fail("no gvr")
+	return schema.GroupVersionResource{}
+}
+
+func gvrWithClosure() schema.GroupVersionResource {
+	other := func() schema.GroupVersionResource {
+		return schema.GroupVersionResource{Group: "b8h2.openshift.io", Version: "v1", Resource: "testdata"}
+	}
+	_ = other
+	return schema.GroupVersionResource{Group: "b8h1.openshift.io", Version: "v1", Resource: "testdata"}
+}
+
+func recursiveGVR(n int) schema.GroupVersionResource {
+	if n == 0 {
+		return schema.GroupVersionResource{Group: "b8i1.openshift.io", Version: "v1", Resource: "testdata"}
+	}
+	return recursiveGVR(n - 1)
+}
+
+func recursiveGVRs(n int) []schema.GroupVersionResource {
+	if n == 0 {
+		return []schema.GroupVersionResource{{Group: "b8j1.openshift.io", Version: "v1", Resource: "testdata"}}
+	}
+	return recursiveGVRs(n - 1)
+}
@@ -0,0 +1,61 @@
+package dynamic_client_go
+
+import (
+	g "github.com/onsi/ginkgo/v2"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var _ = g.Describe("Only the variable passed to Resource() is followed when many are assigned at once", func() {
+	g.It("tuple assignment of literals [apigroup:e1a2.openshift.io]", func() {
+		gvrA, gvrB := localGVR("e1a1.openshift.io", "v1", "testdata"), localGVR("e1a2.openshift.io", "v1", "testdata")
+		_ = gvrA
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvrB)
+	})
+
+	g.It("var declaration of many gvrs [apigroup:e1b1.openshift.io]", func() {
+		var gvrA, gvrB = schema.GroupVersionResource{Group: "e1b1.openshift.io"}, schema.GroupVersionResource{Group: "e1b2.openshift.io"}
+		_ = gvrB
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvrA)
+	})
+
+	g.It("function returning many gvrs [apigroup:e1c2.openshift.io]", func() {
+		_, gvr := twoGVRs()
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvr)
+	})
+
+	g.It("function returning result of another function returning many gvrs [apigroup:e1c1.openshift.io]", func() {
+		gvr, _ := twoGVRsIndirect()
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		_ = dynamicClient.Resource(gvr)
+	})
+
+	g.It("range over map keys [apigroup:e1d1.openshift.io]", func() {
+		m := map[schema.GroupVersionResource]schema.GroupVersionResource{
+			{Group: "e1d1.openshift.io"}: {Group: "e1d2.openshift.io"},
+		}
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for gvr := range m {
+			_ = dynamicClient.Resource(gvr)
+		}
+	})
+
+	g.It("range over map values [apigroup:e1d4.openshift.io]", func() {
+		dynamicClient := dynamic.NewForConfigOrDie(nil)
+		for _, gvr := range map[schema.GroupVersionResource]schema.GroupVersionResource{{Group: "e1d3.openshift.io"}: {Group: "e1d4.openshift.io"}} {
+			_ = dynamicClient.Resource(gvr)
+		}
+	})
+})
+
+func twoGVRs() (schema.GroupVersionResource, schema.GroupVersionResource) {
+	return schema.GroupVersionResource{Group: "e1c1.openshift.io"}, schema.GroupVersionResource{Group: "e1c2.openshift.io"}
+}
+
+func twoGVRsIndirect() (schema.GroupVersionResource, schema.GroupVersionResource) {
+	return twoGVRs()
+}
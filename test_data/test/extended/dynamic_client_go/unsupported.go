@@ -0,0 +1,17 @@
+package dynamic_client_go
+
+import (
+	g "github.com/onsi/ginkgo/v2"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var _ = g.Describe("Unsupported constructs", func() {
+	dynamicClient := dynamic.NewForConfigOrDie(nil)
+
+	g.It("GVR dereferenced from pointer is reported as unsupported construct [apigroup:5d1e.openshift.io]", func() {
+		gvr := &schema.GroupVersionResource{Group: "5d1e.openshift.io", Version: "v1", Resource: "testdata"}
+		_ = dynamicClient.Resource(*gvr)
+	})
+})
@@ -0,0 +1,60 @@
+package kubernetes_client_go
+
+import (
+	"context"
+
+	g "github.com/onsi/ginkgo/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	typedappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+
+	"github.com/pmtk/openshift-tests-api-usage/test_data/test/e2e/framework"
+	exutil "github.com/pmtk/openshift-tests-api-usage/test_data/test/extended/util"
+)
+
+// scaleDeployment gets client of deployments as a parameter
+func scaleDeployment(ctx context.Context, deployments typedappsv1.DeploymentInterface, name string) {
+	_, _ = deployments.GetScale(ctx, name, metav1.GetOptions{})
+}
+
+var _ = g.Describe("Kubernetes typed clientset", func() {
+	oc := exutil.NewCLI("typed")
+	f := framework.NewDefaultFramework("typed")
+	ctx := context.Background()
+
+	g.It("pods of admin client [apigroup:core]", func() {
+		_, _ = oc.AdminKubeClient().CoreV1().Pods(oc.Namespace()).List(ctx, metav1.ListOptions{})
+	})
+
+	g.It("deployments of framework's clientset [apigroup:apps]", func() {
+		_, _ = f.ClientSet.AppsV1().Deployments(oc.Namespace()).Get(ctx, "name", metav1.GetOptions{})
+	})
+
+	g.It("cluster roles of clientset in variable [apigroup:rbac.authorization.k8s.io]", func() {
+		var kubeClient kubernetes.Interface = oc.KubeFramework().ClientSet
+		roles := kubeClient.RbacV1().ClusterRoles()
+		_ = roles.Delete(ctx, "name", metav1.DeleteOptions{})
+	})
+
+	g.It("resource client passed to helper [apigroup:apps]", func() {
+		scaleDeployment(ctx, f.ClientSet.AppsV1().Deployments("ns"), "name")
+	})
+
+	g.It("many groups [apigroup:core][apigroup:batch][apigroup:storage.k8s.io]", func() {
+		kubeClient := oc.AdminKubeClient()
+		_, _ = kubeClient.CoreV1().ConfigMaps("ns").Create(ctx, &corev1.ConfigMap{}, metav1.CreateOptions{})
+		_, _ = kubeClient.BatchV1().Jobs("ns").Watch(ctx, metav1.ListOptions{})
+		_, _ = kubeClient.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	})
+
+	g.It("eviction of pod by policy client [apigroup:core]", func() {
+		_ = oc.AdminKubeClient().PolicyV1().Evictions("ns").Evict(ctx, &policyv1.Eviction{})
+	})
+
+	g.It("group client is not API call", func() {
+		_ = oc.AdminKubeClient().CoreV1().RESTClient()
+	})
+})
@@ -0,0 +1,74 @@
+package rest_paths
+
+import (
+	"context"
+	"path"
+
+	g "github.com/onsi/ginkgo/v2"
+
+	"k8s.io/client-go/kubernetes"
+
+	exutil "github.com/pmtk/openshift-tests-api-usage/test_data/test/extended/util"
+)
+
+const (
+	e1c1Group = "e1c1.openshift.io"
+	e1c1Path  = "/apis/" + e1c1Group + "/v1"
+)
+
+// buildsPath builds path of namespaced resource
+func buildsPath(ns string) string {
+	return "/apis/e1f1.openshift.io/v1/namespaces/" + ns + "/builds"
+}
+
+var _ = g.Describe("REST paths", func() {
+	oc := exutil.NewCLI("rest-paths")
+	kubeClient := kubernetes.NewForConfigOrDie(nil)
+	ctx := context.Background()
+
+	g.It("literal path [apigroup:e1a1.openshift.io]", func() {
+		_ = kubeClient.CoreV1().RESTClient().Get().AbsPath("/apis/e1a1.openshift.io/v1/clusteroperators").Do(ctx)
+	})
+
+	g.It("path segments [apigroup:e1b1.openshift.io]", func() {
+		_ = kubeClient.CoreV1().RESTClient().Get().AbsPath("/apis", "e1b1.openshift.io", "v1", "namespaces", oc.Namespace(), "routes").Do(ctx)
+	})
+
+	g.It("concatenated constants [apigroup:e1c1.openshift.io]", func() {
+		_ = kubeClient.CoreV1().RESTClient().Get().AbsPath(e1c1Path + "/clusterversions/version/status").Do(ctx)
+	})
+
+	g.It("path.Join assigned to variable [apigroup:e1d1.openshift.io]", func() {
+		p := path.Join("/apis", "e1d1.openshift.io", "v1")
+		_ = kubeClient.Discovery().RESTClient().Get().AbsPath(p).Do(ctx)
+	})
+
+	g.It("request URI with namespace [apigroup:e1e1.openshift.io]", func() {
+		_ = kubeClient.CoreV1().RESTClient().Get().RequestURI("/apis/e1e1.openshift.io/v1/namespaces/" + oc.Namespace() + "/images?limit=1").Do(ctx)
+	})
+
+	g.It("path returned by helper [apigroup:e1f1.openshift.io]", func() {
+		_ = kubeClient.CoreV1().RESTClient().Get().AbsPath(buildsPath(oc.Namespace())).Do(ctx)
+	})
+
+	g.It("discovery of API Group [apigroup:e1g1.openshift.io]", func() {
+		_ = kubeClient.Discovery().RESTClient().Get().AbsPath("/apis/e1g1.openshift.io").Do(ctx)
+	})
+
+	g.It("core API Group path", func() {
+		_ = kubeClient.CoreV1().RESTClient().Get().AbsPath("/api/v1/namespaces/default/pods").Do(ctx)
+	})
+
+	g.It("non-API path", func() {
+		_ = kubeClient.CoreV1().RESTClient().Get().AbsPath("/healthz").Do(ctx)
+	})
+
+	g.It("non-API path in variable", func() {
+		p := "/metrics"
+		_ = kubeClient.CoreV1().RESTClient().Get().AbsPath(p).Do(ctx)
+	})
+
+	g.It("non-API path with unknown segment", func() {
+		_ = kubeClient.CoreV1().RESTClient().Get().AbsPath("/readyz/" + oc.Namespace()).Do(ctx)
+	})
+})
@@ -0,0 +1,92 @@
+// Package util mimics origin's test/extended/util (imported as exutil) with just enough of CLI for fixtures.
+package util
+
+import (
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	imageclient "github.com/openshift/client-go/image/clientset/versioned"
+	operatorclient "github.com/openshift/client-go/operator/clientset/versioned"
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/pmtk/openshift-tests-api-usage/test_data/test/e2e/framework"
+)
+
+// CLI runs oc commands in the test's namespace
+type CLI struct {
+	namespace string
+	asAdmin   bool
+	verb      string
+	args      []string
+}
+
+func NewCLI(project string) *CLI {
+	return &CLI{namespace: project}
+}
+
+// Namespace returns the namespace of the test
+func (c *CLI) Namespace() string {
+	return c.namespace
+}
+
+// AdminKubeClient returns clientset of upstream Kubernetes APIs for cluster admin
+func (c *CLI) AdminKubeClient() kubernetes.Interface {
+	return kubernetes.NewForConfigOrDie(nil)
+}
+
+// AdminConfigClient returns clientset of config.openshift.io for cluster admin
+func (c *CLI) AdminConfigClient() configclient.Interface {
+	return configclient.NewForConfigOrDie(nil)
+}
+
+// AdminRouteClient returns clientset of route.openshift.io for cluster admin
+func (c *CLI) AdminRouteClient() routeclient.Interface {
+	return routeclient.NewForConfigOrDie(nil)
+}
+
+// AdminImageClient returns clientset of image.openshift.io for cluster admin
+func (c *CLI) AdminImageClient() imageclient.Interface {
+	return imageclient.NewForConfigOrDie(nil)
+}
+
+// AdminOperatorClient returns clientset of operator.openshift.io for cluster admin
+func (c *CLI) AdminOperatorClient() operatorclient.Interface {
+	return operatorclient.NewForConfigOrDie(nil)
+}
+
+// AdminDynamicClient returns dynamic client for cluster admin
+func (c *CLI) AdminDynamicClient() dynamic.Interface {
+	return dynamic.NewForConfigOrDie(nil)
+}
+
+// KubeFramework returns e2e framework of the test
+func (c *CLI) KubeFramework() *framework.Framework {
+	return framework.NewDefaultFramework(c.namespace)
+}
+
+// AsAdmin makes the command run as cluster admin
+func (c *CLI) AsAdmin() *CLI {
+	nc := *c
+	nc.asAdmin = true
+	return &nc
+}
+
+// Run prepares oc command, e.g. Run("get")
+func (c *CLI) Run(commands ...string) *CLI {
+	nc := *c
+	nc.verb = commands[0]
+	nc.args = commands[1:]
+	return &nc
+}
+
+// Args appends arguments of the command
+func (c *CLI) Args(args ...string) *CLI {
+	nc := *c
+	nc.args = append(append([]string{}, c.args...), args...)
+	return &nc
+}
+
+// Output runs the command and returns its output
+func (c *CLI) Output() (string, error) {
+	return "", nil
+}
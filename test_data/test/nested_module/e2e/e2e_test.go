@@ -0,0 +1,17 @@
+// mimics operator repositories whose e2e tests live in a separate Go module
+
+package e2e
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+func TestNestedModule(t *testing.T) {
+	dynamicClient := dynamic.NewForConfigOrDie(nil)
+	t.Run("resource of nested module [apigroup:f2a1.openshift.io]", func(t *testing.T) {
+		_ = dynamicClient.Resource(schema.GroupVersionResource{Group: "f2a1.openshift.io", Version: "v1", Resource: "testdata"})
+	})
+}